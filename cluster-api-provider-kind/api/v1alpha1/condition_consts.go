@@ -0,0 +1,96 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// KindClusterProvisionedCondition reports on whether the Kind cluster has been created and is running
+	KindClusterProvisionedCondition clusterv1.ConditionType = "KindClusterProvisioned"
+
+	// WaitingForKindClusterReason is used when the Kind cluster exists but is not running yet
+	WaitingForKindClusterReason = "WaitingForKindCluster"
+	// WaitingForRawConfigReason is used when the ConfigMap referenced by rawConfigRef cannot be read
+	WaitingForRawConfigReason = "WaitingForRawConfig"
+	// KindClusterCreationFailedReason is used when the Kind Wrapper API fails to create the Kind cluster
+	KindClusterCreationFailedReason = "KindClusterCreationFailed"
+	// KindClusterInvalidConfigReason is used when the Kind Wrapper API rejects the Kind cluster configuration
+	KindClusterInvalidConfigReason = "KindClusterInvalidConfig"
+	// KindClusterFailedReason is used when the Kind Wrapper API reports the Kind cluster as failed
+	KindClusterFailedReason = "KindClusterFailed"
+	// KindHostUnavailableReason is used when the KindHost or its credentials cannot be read
+	KindHostUnavailableReason = "KindHostUnavailable"
+	// WaitingForHostCapacityReason is used when the KindHost has no capacity left for the Kind cluster
+	WaitingForHostCapacityReason = "WaitingForHostCapacity"
+	// KindClusterNotFoundReason is used when the Kind cluster to adopt does not exist
+	KindClusterNotFoundReason = "KindClusterNotFound"
+	// WaitingForRetryReason is used when the creation of the Kind cluster failed and it is retried after a backoff
+	WaitingForRetryReason = "WaitingForRetry"
+	// ProvisioningTimeoutReason is used when the Kind cluster is not running within the provisioning timeout
+	ProvisioningTimeoutReason = "ProvisioningTimeout"
+	// ProvisioningRetriesExceededReason is used when the creation of the Kind cluster failed more times than the retries allow
+	ProvisioningRetriesExceededReason = "ProvisioningRetriesExceeded"
+	// KindClusterLostReason is used when the running Kind cluster no longer exists
+	KindClusterLostReason = "ClusterLost"
+	// KindClusterDeletingReason is used when the Kind cluster is being deleted
+	KindClusterDeletingReason = "Deleting"
+
+	// KindClusterSpecAppliedCondition reports on whether the Kind cluster runs with the Kind configuration currently defined by the spec
+	KindClusterSpecAppliedCondition clusterv1.ConditionType = "KindClusterSpecApplied"
+
+	// KindClusterRecreatingReason is used when the Kind cluster is being recreated to apply a changed spec
+	KindClusterRecreatingReason = "Recreating"
+	// KindClusterSpecDriftedReason is used when the spec changed but the update strategy does not allow to apply it
+	KindClusterSpecDriftedReason = "SpecDrifted"
+
+	// KindClusterLostCondition reports on whether the running Kind cluster no longer exists
+	// It is True from the loss until the Kind cluster is running again and False afterwards, it is not set for Kind clusters which were never lost
+	KindClusterLostCondition clusterv1.ConditionType = "ClusterLost"
+
+	// KindClusterRecoveredReason is used when the lost Kind cluster is running again
+	KindClusterRecoveredReason = "Recovered"
+
+	// KindHostScheduledCondition reports on whether a KindHost has been selected for a KindCluster without a host reference
+	KindHostScheduledCondition clusterv1.ConditionType = "KindHostScheduled"
+
+	// NoKindHostAvailableReason is used when no KindHost is suitable for the Kind cluster
+	NoKindHostAvailableReason = "NoKindHostAvailable"
+	// ReschedulingReason is used when the Kind cluster is scheduled again after it failed on the selected KindHost
+	ReschedulingReason = "Rescheduling"
+
+	// KindHostAvailableCondition reports on whether the Kind Wrapper API of a KindHost responds
+	KindHostAvailableCondition clusterv1.ConditionType = "KindHostAvailable"
+
+	// KindHostUnreachableReason is used when the Kind Wrapper API of a KindHost cannot be reached
+	KindHostUnreachableReason = "KindHostUnreachable"
+	// KindAPICircuitOpenReason is used when the circuit breaker stops the calls of a Kind Wrapper API after repeated failures
+	KindAPICircuitOpenReason = "CircuitOpen"
+
+	// KindAPIAvailableCondition reports on whether the Kind Wrapper API the Kind cluster is created on responds
+	KindAPIAvailableCondition clusterv1.ConditionType = "KindAPIAvailable"
+
+	// KindAPIUnreachableReason is used when a call of the Kind Wrapper API fails
+	KindAPIUnreachableReason = "KindAPIUnreachable"
+
+	// ControlPlaneReachableCondition reports on whether the control plane endpoint of the Kind cluster accepts connections
+	ControlPlaneReachableCondition clusterv1.ConditionType = "ControlPlaneReachable"
+
+	// ControlPlaneUnreachableReason is used when a connection to the control plane endpoint cannot be established
+	ControlPlaneUnreachableReason = "ControlPlaneUnreachable"
+)
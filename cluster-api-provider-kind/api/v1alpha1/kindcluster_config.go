@@ -0,0 +1,143 @@
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"strings"
+)
+
+const (
+	kindConfigKind       = "Cluster"
+	kindConfigAPIVersion = "kind.x-k8s.io/v1alpha4"
+)
+
+// InvalidKindConfigError is returned by the ParseKindConfig function in case the raw config is not a valid Kind config
+var InvalidKindConfigError = errors.New("invalid kind cluster config")
+
+// ParseKindConfig strictly decodes a raw Kind cluster configuration YAML in the kind.x-k8s.io/v1alpha4 format
+// Unknown fields are rejected the same way Kind rejects them
+func ParseKindConfig(rawConfig string) (*v1alpha4.Cluster, error) {
+	config := &v1alpha4.Cluster{}
+	decoder := yaml.NewDecoder(strings.NewReader(rawConfig))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("%w: %s", InvalidKindConfigError, err)
+	}
+	if config.Kind != kindConfigKind || config.APIVersion != kindConfigAPIVersion {
+		return nil, fmt.Errorf("%w: unsupported kind %q and apiVersion %q", InvalidKindConfigError, config.Kind, config.APIVersion)
+	}
+	return config, nil
+}
+
+// HasStructuredConfig checks if any of the fields converted to the Kind configuration is set
+func (s *KindClusterSpec) HasStructuredConfig() bool {
+	return len(s.FeatureGates) > 0 ||
+		len(s.RuntimeConfig) > 0 ||
+		s.Networking != KindClusterNetworking{} ||
+		len(s.Nodes) > 0 ||
+		len(s.KubeAdmConfigPatches) > 0 ||
+		len(s.KubeAdmConfigPatchesJSON6902) > 0 ||
+		len(s.ContainerdConfigPatches) > 0 ||
+		len(s.ContainerdConfigPatchesJSON6902) > 0
+}
+
+// KindConfigHash computes a hash of the Kind configuration defined by the spec, including the raw config and its reference
+// Fields which do not affect the Kind cluster, such as the control plane endpoint or the update strategy, are ignored
+func (s *KindClusterSpec) KindConfigHash() string {
+	data, _ := json.Marshal(struct {
+		Config       *v1alpha4.Cluster
+		RawConfig    string
+		RawConfigRef *KindClusterConfigMapKeyReference
+	}{s.KindConfig(""), s.RawConfig, s.RawConfigRef})
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// NodeCount returns the number of Kind nodes defined by the spec, Kind creates a single node if none is defined
+// Raw config references have to be resolved first, otherwise a single node is assumed
+func (s *KindClusterSpec) NodeCount() int {
+	nodes := len(s.Nodes)
+	if s.RawConfig != "" {
+		if config, err := ParseKindConfig(s.RawConfig); err == nil {
+			nodes = len(config.Nodes)
+		}
+	}
+	if nodes == 0 {
+		return 1
+	}
+	return nodes
+}
+
+// KindConfig converts the spec to a Kind cluster configuration with the specified cluster name
+// ControlPlaneEndpoint is not a part of the Kind configuration, so it is not converted
+func (s *KindClusterSpec) KindConfig(name string) *v1alpha4.Cluster {
+	config := &v1alpha4.Cluster{
+		TypeMeta: v1alpha4.TypeMeta{
+			Kind:       kindConfigKind,
+			APIVersion: kindConfigAPIVersion,
+		},
+		Name:                            name,
+		FeatureGates:                    s.FeatureGates,
+		RuntimeConfig:                   s.RuntimeConfig,
+		KubeadmConfigPatches:            s.KubeAdmConfigPatches,
+		KubeadmConfigPatchesJSON6902:    toKindPatches(s.KubeAdmConfigPatchesJSON6902),
+		ContainerdConfigPatches:         s.ContainerdConfigPatches,
+		ContainerdConfigPatchesJSON6902: s.ContainerdConfigPatchesJSON6902,
+		Networking: v1alpha4.Networking{
+			IPFamily:          v1alpha4.ClusterIPFamily(s.Networking.IPFamily),
+			APIServerPort:     int32(s.Networking.APIServerPort),
+			APIServerAddress:  s.Networking.APIServerAddress,
+			PodSubnet:         s.Networking.PodSubnet,
+			ServiceSubnet:     s.Networking.ServiceSubnet,
+			DisableDefaultCNI: s.Networking.DisableDefaultCNI,
+			KubeProxyMode:     v1alpha4.ProxyMode(s.Networking.KubeProxyMode),
+			DNSSearch:         s.Networking.DNSSearch,
+		},
+	}
+	for _, node := range s.Nodes {
+		kindNode := v1alpha4.Node{
+			Role:                         v1alpha4.NodeRole(node.Role),
+			Image:                        node.Image,
+			Labels:                       node.Labels,
+			KubeadmConfigPatches:         node.KubeAdmConfigPatches,
+			KubeadmConfigPatchesJSON6902: toKindPatches(node.KubeAdmConfigPatchesJSON6902),
+		}
+		for _, mount := range node.ExtraMounts {
+			kindNode.ExtraMounts = append(kindNode.ExtraMounts, v1alpha4.Mount{
+				ContainerPath:  mount.ContainerPath,
+				HostPath:       mount.HostPath,
+				Readonly:       mount.ReadOnly,
+				SelinuxRelabel: mount.SELinuxRelabel,
+				Propagation:    v1alpha4.MountPropagation(mount.Propagation),
+			})
+		}
+		for _, mapping := range node.ExtraPortMappings {
+			kindNode.ExtraPortMappings = append(kindNode.ExtraPortMappings, v1alpha4.PortMapping{
+				ContainerPort: int32(mapping.ContainerPort),
+				HostPort:      int32(mapping.HostPort),
+				ListenAddress: mapping.ListenAddress,
+				Protocol:      v1alpha4.PortMappingProtocol(mapping.Protocol),
+			})
+		}
+		config.Nodes = append(config.Nodes, kindNode)
+	}
+	return config
+}
+
+func toKindPatches(patches []KindClusterPatchJSON6902) []v1alpha4.PatchJSON6902 {
+	var kindPatches []v1alpha4.PatchJSON6902
+	for _, patch := range patches {
+		kindPatches = append(kindPatches, v1alpha4.PatchJSON6902{
+			Group:   patch.Group,
+			Version: patch.Version,
+			Kind:    patch.Kind,
+			Patch:   patch.Patch,
+		})
+	}
+	return kindPatches
+}
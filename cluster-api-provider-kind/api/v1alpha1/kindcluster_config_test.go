@@ -0,0 +1,193 @@
+package v1alpha1
+
+import (
+	"bytes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+var _ = Describe("KindCluster config conversion", func() {
+
+	var spec KindClusterSpec
+
+	BeforeEach(func() {
+		spec = KindClusterSpec{
+			FeatureGates:  map[string]bool{"EphemeralContainers": true},
+			RuntimeConfig: map[string]string{"api/alpha": "false"},
+			Networking: KindClusterNetworking{
+				IPFamily:          KindClusterIPFamilyDual,
+				APIServerAddress:  "10.10.10.1",
+				APIServerPort:     6443,
+				PodSubnet:         "10.244.0.0/16",
+				ServiceSubnet:     "10.96.0.0/12",
+				DisableDefaultCNI: true,
+				KubeProxyMode:     KindClusterKubeProxyModeIPVS,
+				DNSSearch:         &[]string{"example.com"},
+			},
+			Nodes: []KindClusterNode{{
+				Role:                 KindClusterRoleControlPlane,
+				Image:                "kindest/node:v1.26.3",
+				Labels:               map[string]string{"tier": "frontend"},
+				KubeAdmConfigPatches: []string{"kind: InitConfiguration"},
+				KubeAdmConfigPatchesJSON6902: []KindClusterPatchJSON6902{{
+					Group:   "kubeadm.k8s.io",
+					Version: "v1beta3",
+					Kind:    "InitConfiguration",
+					Patch:   "- op: add\n  path: /nodeRegistration/name\n  value: node\n",
+				}},
+				ExtraMounts: []KindClusterExtraMount{{
+					HostPath:       "/tmp",
+					ContainerPath:  "/data",
+					ReadOnly:       true,
+					SELinuxRelabel: true,
+					Propagation:    KindClusterPropagationHostToContainer,
+				}},
+				ExtraPortMappings: []KindClusterExtraPortMapping{{
+					ContainerPort: 80,
+					HostPort:      8080,
+					ListenAddress: "127.0.0.1",
+					Protocol:      KindClusterProtocolUDP,
+				}},
+			}, {
+				Role: KindClusterRoleWorker,
+			}},
+			KubeAdmConfigPatches: []string{"kind: ClusterConfiguration"},
+			KubeAdmConfigPatchesJSON6902: []KindClusterPatchJSON6902{{
+				Group:   "kubeadm.k8s.io",
+				Version: "v1beta3",
+				Kind:    "ClusterConfiguration",
+				Patch:   "- op: add\n  path: /apiServer/certSANs/-\n  value: my-hostname\n",
+			}},
+			ContainerdConfigPatches:         []string{"[plugins.\"io.containerd.grpc.v1.cri\"]"},
+			ContainerdConfigPatchesJSON6902: []string{"- op: remove\n  path: /plugins\n"},
+		}
+	})
+
+	It("should convert the spec to a Kind config", func() {
+		config := spec.KindConfig("default-kind-cluster")
+		Expect(config.Kind).To(Equal("Cluster"))
+		Expect(config.APIVersion).To(Equal("kind.x-k8s.io/v1alpha4"))
+		Expect(config.Name).To(Equal("default-kind-cluster"))
+		Expect(config.Networking.IPFamily).To(Equal(v1alpha4.DualStackFamily))
+		Expect(config.Networking.DNSSearch).To(Equal(&[]string{"example.com"}))
+		Expect(config.ContainerdConfigPatches).To(Equal(spec.ContainerdConfigPatches))
+		Expect(config.KubeadmConfigPatchesJSON6902).To(HaveLen(1))
+		Expect(config.Nodes).To(HaveLen(2))
+		Expect(config.Nodes[0].Labels).To(Equal(map[string]string{"tier": "frontend"}))
+		Expect(config.Nodes[0].ExtraMounts[0].Readonly).To(BeTrue())
+		Expect(config.Nodes[0].ExtraPortMappings[0].Protocol).To(Equal(v1alpha4.PortMappingProtocolUDP))
+		Expect(config.Nodes[1].Role).To(Equal(v1alpha4.WorkerRole))
+	})
+
+	It("should convert the spec to a Kind config and back without losing any fields", func() {
+		Expect(specFromKindConfig(spec.KindConfig("default-kind-cluster"))).To(Equal(spec))
+		Expect(specFromKindConfig((&KindClusterSpec{}).KindConfig("empty"))).To(Equal(KindClusterSpec{}))
+	})
+
+	It("should serialize the Kind config so that it can be strictly decoded by Kind", func() {
+		data, err := yaml.Marshal(spec.KindConfig("default-kind-cluster"))
+		Expect(err).NotTo(HaveOccurred())
+
+		decoded := &v1alpha4.Cluster{}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		Expect(decoder.Decode(decoded)).To(Succeed())
+		Expect(decoded.Name).To(Equal("default-kind-cluster"))
+		Expect(specFromKindConfig(decoded)).To(Equal(spec))
+	})
+
+	It("should not include the control plane endpoint in the Kind config", func() {
+		spec.ControlPlaneEndpoint = KindClusterControlPlaneEndpoint{Host: "10.10.10.1", Port: 6443}
+		data, err := yaml.Marshal(spec.KindConfig("default-kind-cluster"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("controlPlaneEndpoint"))
+	})
+
+	It("should compute the hash only from the fields affecting the Kind config", func() {
+		hash := spec.KindConfigHash()
+		Expect(spec.DeepCopy().KindConfigHash()).To(Equal(hash))
+
+		changed := spec.DeepCopy()
+		changed.ControlPlaneEndpoint = KindClusterControlPlaneEndpoint{Host: "10.10.10.1", Port: 6443}
+		changed.UpdateStrategy = KindClusterUpdateStrategyRecreate
+		Expect(changed.KindConfigHash()).To(Equal(hash))
+
+		changed.Nodes = changed.Nodes[:1]
+		Expect(changed.KindConfigHash()).NotTo(Equal(hash))
+
+		raw := KindClusterSpec{RawConfig: "kind: Cluster"}
+		Expect(raw.KindConfigHash()).NotTo(Equal((&KindClusterSpec{RawConfig: "kind: Cluster\n"}).KindConfigHash()))
+	})
+
+	It("should count the nodes of the Kind config", func() {
+		Expect(spec.NodeCount()).To(Equal(2))
+		Expect((&KindClusterSpec{}).NodeCount()).To(Equal(1))
+		Expect((&KindClusterSpec{RawConfig: "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n- role: control-plane\n- role: worker\n- role: worker\n"}).NodeCount()).To(Equal(3))
+	})
+})
+
+// specFromKindConfig converts a Kind cluster configuration back to a spec, so that the conversion can be checked for lost fields
+// The cluster name is not a part of the spec, so it is not converted
+func specFromKindConfig(config *v1alpha4.Cluster) KindClusterSpec {
+	spec := KindClusterSpec{
+		FeatureGates:                    config.FeatureGates,
+		RuntimeConfig:                   config.RuntimeConfig,
+		KubeAdmConfigPatches:            config.KubeadmConfigPatches,
+		KubeAdmConfigPatchesJSON6902:    fromKindPatches(config.KubeadmConfigPatchesJSON6902),
+		ContainerdConfigPatches:         config.ContainerdConfigPatches,
+		ContainerdConfigPatchesJSON6902: config.ContainerdConfigPatchesJSON6902,
+		Networking: KindClusterNetworking{
+			IPFamily:          KindClusterIPFamily(config.Networking.IPFamily),
+			APIServerAddress:  config.Networking.APIServerAddress,
+			APIServerPort:     int(config.Networking.APIServerPort),
+			PodSubnet:         config.Networking.PodSubnet,
+			ServiceSubnet:     config.Networking.ServiceSubnet,
+			DisableDefaultCNI: config.Networking.DisableDefaultCNI,
+			KubeProxyMode:     KindClusterKubeProxyMode(config.Networking.KubeProxyMode),
+			DNSSearch:         config.Networking.DNSSearch,
+		},
+	}
+	for _, kindNode := range config.Nodes {
+		node := KindClusterNode{
+			Role:                         KindClusterRole(kindNode.Role),
+			Image:                        kindNode.Image,
+			Labels:                       kindNode.Labels,
+			KubeAdmConfigPatches:         kindNode.KubeadmConfigPatches,
+			KubeAdmConfigPatchesJSON6902: fromKindPatches(kindNode.KubeadmConfigPatchesJSON6902),
+		}
+		for _, mount := range kindNode.ExtraMounts {
+			node.ExtraMounts = append(node.ExtraMounts, KindClusterExtraMount{
+				HostPath:       mount.HostPath,
+				ContainerPath:  mount.ContainerPath,
+				ReadOnly:       mount.Readonly,
+				SELinuxRelabel: mount.SelinuxRelabel,
+				Propagation:    KindClusterPropagation(mount.Propagation),
+			})
+		}
+		for _, mapping := range kindNode.ExtraPortMappings {
+			node.ExtraPortMappings = append(node.ExtraPortMappings, KindClusterExtraPortMapping{
+				ContainerPort: int(mapping.ContainerPort),
+				HostPort:      int(mapping.HostPort),
+				ListenAddress: mapping.ListenAddress,
+				Protocol:      KindClusterProtocol(mapping.Protocol),
+			})
+		}
+		spec.Nodes = append(spec.Nodes, node)
+	}
+	return spec
+}
+
+func fromKindPatches(kindPatches []v1alpha4.PatchJSON6902) []KindClusterPatchJSON6902 {
+	var patches []KindClusterPatchJSON6902
+	for _, patch := range kindPatches {
+		patches = append(patches, KindClusterPatchJSON6902{
+			Group:   patch.Group,
+			Version: patch.Version,
+			Kind:    patch.Kind,
+			Patch:   patch.Patch,
+		})
+	}
+	return patches
+}
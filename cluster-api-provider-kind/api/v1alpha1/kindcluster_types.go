@@ -17,7 +17,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"time"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -29,6 +34,9 @@ type KindClusterRole string
 type KindClusterIPFamily string
 type KindClusterKubeProxyMode string
 type KindClusterState string
+type KindClusterUpdateStrategy string
+type KindClusterDeletionPolicy string
+type KindClusterRecoveryPolicy string
 
 const (
 	KindClusterProtocolTCP  = KindClusterProtocol("TCP")
@@ -42,19 +50,55 @@ const (
 	KindClusterRoleControlPlane = KindClusterRole("control-plane")
 	KindClusterRoleWorker       = KindClusterRole("worker")
 
-	KindCLusterIPFamilyV4 = KindClusterIPFamily("ipv4")
-	KindCLusterIPFamilyV6 = KindClusterIPFamily("ipv6")
+	KindCLusterIPFamilyV4   = KindClusterIPFamily("ipv4")
+	KindCLusterIPFamilyV6   = KindClusterIPFamily("ipv6")
+	KindClusterIPFamilyDual = KindClusterIPFamily("dual")
 
 	KindClusterKubeProxyModeNone     = KindClusterKubeProxyMode("none")
 	KindClusterKubeProxyModeIPTables = KindClusterKubeProxyMode("iptables")
 	KindClusterKubeProxyModeIPVS     = KindClusterKubeProxyMode("ipvs")
 
-	KindClusterStatePending  = KindClusterState("Pending")
-	KindClusterStateRunning  = KindClusterState("Running")
-	KindClusterStateDeleting = KindClusterState("Deleting")
-	KindClusterStateFailed   = KindClusterState("Failed")
+	KindClusterStatePending    = KindClusterState("Pending")
+	KindClusterStateRunning    = KindClusterState("Running")
+	KindClusterStateDeleting   = KindClusterState("Deleting")
+	KindClusterStateFailed     = KindClusterState("Failed")
+	KindClusterStateRecreating = KindClusterState("Recreating")
+
+	KindClusterUpdateStrategyImmutable = KindClusterUpdateStrategy("Immutable")
+	KindClusterUpdateStrategyRecreate  = KindClusterUpdateStrategy("Recreate")
+
+	KindClusterDeletionPolicyDelete = KindClusterDeletionPolicy("Delete")
+	KindClusterDeletionPolicyOrphan = KindClusterDeletionPolicy("Orphan")
+	KindClusterDeletionPolicyRetain = KindClusterDeletionPolicy("Retain")
+
+	KindClusterRecoveryPolicyRecreate = KindClusterRecoveryPolicy("Recreate")
+	KindClusterRecoveryPolicyFail     = KindClusterRecoveryPolicy("Fail")
 
 	KindClusterFinalizerName = "kindcluster.finalizers.infrastructure.cluster.x-k8s.io"
+
+	DefaultProvisioningTimeout = 10 * time.Minute
+
+	// KindClusterHostAnnotation records the KindHost the Kind cluster was created on
+	// Unlike the status, annotations are kept when the KindCluster is moved to another management cluster by clusterctl
+	KindClusterHostAnnotation = "infrastructure.cluster.x-k8s.io/kind-host"
+
+	// KindClusterAdoptAnnotation names an existing Kind cluster, which is adopted by the KindCluster instead of creating a new one
+	KindClusterAdoptAnnotation = "infrastructure.cluster.x-k8s.io/adopt-kind-cluster"
+
+	// KindClusterRetryAnnotation resets the attempts of a failed KindCluster and creates the Kind cluster again, it is removed once handled
+	KindClusterRetryAnnotation = "infrastructure.cluster.x-k8s.io/retry"
+
+	// KindClusterExtendTTLAnnotation extends the TTL of the KindCluster by a duration, e.g. 2h, it is kept, so raise the duration to extend the TTL further
+	KindClusterExtendTTLAnnotation = "infrastructure.cluster.x-k8s.io/extend-ttl"
+
+	// KindClusterTTLStartAnnotation records the time the TTL of the KindCluster started in RFC 3339 format, it is set by the controller
+	// The annotation is moved with the KindCluster by clusterctl, so that the TTL does not start over with the new creation time
+	KindClusterTTLStartAnnotation = "infrastructure.cluster.x-k8s.io/ttl-start"
+
+	// The labels identifying the KindCluster of a Kind cluster, they are recorded by the Kind Wrapper API with the labels of the KindCluster
+	KindClusterNamespaceLabel = "infrastructure.cluster.x-k8s.io/kindcluster-namespace"
+	KindClusterNameLabel      = "infrastructure.cluster.x-k8s.io/kindcluster-name"
+	KindClusterUIDLabel       = "infrastructure.cluster.x-k8s.io/kindcluster-uid"
 )
 
 // KindClusterExtraPortMapping defines configuration of extra port mappings in KindClusterNode
@@ -74,13 +118,23 @@ type KindClusterExtraMount struct {
 	Propagation    KindClusterPropagation `json:"propagation,omitempty" yaml:"propagation,omitempty"`
 }
 
+// KindClusterPatchJSON6902 defines a JSON 6902 patch applied to the kubeadm config objects of the specified kind
+type KindClusterPatchJSON6902 struct {
+	Group   string `json:"group" yaml:"group"`
+	Version string `json:"version" yaml:"version"`
+	Kind    string `json:"kind" yaml:"kind"`
+	Patch   string `json:"patch" yaml:"patch"`
+}
+
 // KindClusterNode defines configuration a node in a Kind cluster
 type KindClusterNode struct {
-	Role                 KindClusterRole               `json:"role,omitempty" yaml:"role,omitempty"`
-	Image                string                        `json:"image,omitempty" yaml:"image,omitempty"`
-	KubeAdmConfigPatches []string                      `json:"kubeadmConfigPatches,omitempty" yaml:"kubeadmConfigPatches,omitempty"`
-	ExtraMounts          []KindClusterExtraMount       `json:"extraMounts,omitempty" yaml:"extraMounts,omitempty"`
-	ExtraPortMappings    []KindClusterExtraPortMapping `json:"extraPortMappings,omitempty" yaml:"extraPortMappings,omitempty"`
+	Role                         KindClusterRole               `json:"role,omitempty" yaml:"role,omitempty"`
+	Image                        string                        `json:"image,omitempty" yaml:"image,omitempty"`
+	Labels                       map[string]string             `json:"labels,omitempty" yaml:"labels,omitempty"`
+	KubeAdmConfigPatches         []string                      `json:"kubeadmConfigPatches,omitempty" yaml:"kubeadmConfigPatches,omitempty"`
+	KubeAdmConfigPatchesJSON6902 []KindClusterPatchJSON6902    `json:"kubeadmConfigPatchesJSON6902,omitempty" yaml:"kubeadmConfigPatchesJSON6902,omitempty"`
+	ExtraMounts                  []KindClusterExtraMount       `json:"extraMounts,omitempty" yaml:"extraMounts,omitempty"`
+	ExtraPortMappings            []KindClusterExtraPortMapping `json:"extraPortMappings,omitempty" yaml:"extraPortMappings,omitempty"`
 }
 
 // KindClusterNetworking defines a networking configuration of a Kind cluster
@@ -92,6 +146,9 @@ type KindClusterNetworking struct {
 	ServiceSubnet     string                   `json:"serviceSubnet,omitempty" yaml:"serviceSubnet,omitempty"`
 	DisableDefaultCNI bool                     `json:"disableDefaultCNI,omitempty" yaml:"disableDefaultCNI,omitempty"`
 	KubeProxyMode     KindClusterKubeProxyMode `json:"kubeProxyMode,omitempty" yaml:"kubeProxyMode,omitempty"`
+	// DNSSearch defines the DNS search domains of the nodes, an empty list disables search domains
+	// The search domains are inherited from the host if the field is not set
+	DNSSearch *[]string `json:"dnsSearch,omitempty" yaml:"dnsSearch,omitempty"`
 }
 
 // KindClusterControlPlaneEndpoint defines host and port of the cluster control plane
@@ -100,13 +157,98 @@ type KindClusterControlPlaneEndpoint struct {
 	Port int    `json:"port,omitempty" yaml:"port,omitempty"`
 }
 
+// KindClusterConfigMapKeyReference references a key of a ConfigMap in the namespace of the KindCluster
+type KindClusterConfigMapKeyReference struct {
+	Name string `json:"name" yaml:"name"`
+	Key  string `json:"key" yaml:"key"`
+}
+
+// KindHostReference references a cluster-scoped KindHost
+type KindHostReference struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+// KindClusterScheduling defines how a KindHost is selected for a KindCluster without a host reference
+type KindClusterScheduling struct {
+	// HostSelector selects the KindHosts the Kind cluster can be scheduled on, all KindHosts are considered if not set
+	HostSelector *metav1.LabelSelector `json:"hostSelector,omitempty" yaml:"hostSelector,omitempty"`
+	// NodeResources are the resources (cpu, memory and ephemeral-storage) required by every Kind node
+	// KindHosts reporting less free resources than required by all nodes are skipped
+	NodeResources corev1.ResourceList `json:"nodeResources,omitempty" yaml:"nodeResources,omitempty"`
+	// RescheduleOnFailure schedules the Kind cluster on another KindHost when the Kind cluster fails to be created
+	RescheduleOnFailure bool `json:"rescheduleOnFailure,omitempty" yaml:"rescheduleOnFailure,omitempty"`
+}
+
+// KindClusterSchedulingStatus records the scheduling decision of a KindCluster without a host reference
+type KindClusterSchedulingStatus struct {
+	// Host is the name of the selected KindHost, empty if no KindHost is suitable
+	Host string `json:"host,omitempty"`
+	// Message explains the scheduling decision
+	Message string `json:"message,omitempty"`
+	// LastScheduleTime is the time of the scheduling decision
+	LastScheduleTime metav1.Time `json:"lastScheduleTime,omitempty"`
+	// FailedHosts lists the KindHosts the Kind cluster failed to be created on, they are skipped when rescheduling
+	FailedHosts []string `json:"failedHosts,omitempty"`
+}
+
+// KindClusterProvisioning defines how long and how many times the creation of the Kind cluster is attempted
+type KindClusterProvisioning struct {
+	// Timeout is the maximum duration of an attempt to create the Kind cluster until it is running, 10 minutes if not set
+	Timeout *metav1.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// MaxRetries is the number of times a failed attempt is retried with an exponential backoff, failed attempts are not retried if not set
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int32 `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+}
+
+// KindClusterProvisioningStatus records the attempts to create the Kind cluster
+type KindClusterProvisioningStatus struct {
+	// Attempts is the number of attempts to create the Kind cluster
+	Attempts int32 `json:"attempts,omitempty"`
+	// StartTime is the start time of the current attempt, it is not set while no attempt is in progress
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// NextAttemptTime is the earliest time of the next attempt after a failed one
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+	// LastFailure describes why the last attempt failed
+	LastFailure string `json:"lastFailure,omitempty"`
+}
+
 // KindClusterSpec defines the desired state of KindCluster
 type KindClusterSpec struct {
-	FeatureGates         map[string]bool                 `json:"featureGates,omitempty" yaml:"featureGates,omitempty"`
-	RuntimeConfig        map[string]string               `json:"runtimeConfig,omitempty" yaml:"runtimeConfig,omitempty"`
-	Networking           KindClusterNetworking           `json:"networking,omitempty" yaml:"networking,omitempty"`
-	Nodes                []KindClusterNode               `json:"nodes,omitempty" yaml:"nodes,omitempty"`
-	ControlPlaneEndpoint KindClusterControlPlaneEndpoint `json:"controlPlaneEndpoint,omitempty" yaml:"controlPlaneEndpoint,omitempty"`
+	FeatureGates                    map[string]bool                 `json:"featureGates,omitempty" yaml:"featureGates,omitempty"`
+	RuntimeConfig                   map[string]string               `json:"runtimeConfig,omitempty" yaml:"runtimeConfig,omitempty"`
+	Networking                      KindClusterNetworking           `json:"networking,omitempty" yaml:"networking,omitempty"`
+	Nodes                           []KindClusterNode               `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	KubeAdmConfigPatches            []string                        `json:"kubeadmConfigPatches,omitempty" yaml:"kubeadmConfigPatches,omitempty"`
+	KubeAdmConfigPatchesJSON6902    []KindClusterPatchJSON6902      `json:"kubeadmConfigPatchesJSON6902,omitempty" yaml:"kubeadmConfigPatchesJSON6902,omitempty"`
+	ContainerdConfigPatches         []string                        `json:"containerdConfigPatches,omitempty" yaml:"containerdConfigPatches,omitempty"`
+	ContainerdConfigPatchesJSON6902 []string                        `json:"containerdConfigPatchesJSON6902,omitempty" yaml:"containerdConfigPatchesJSON6902,omitempty"`
+	ControlPlaneEndpoint            KindClusterControlPlaneEndpoint `json:"controlPlaneEndpoint,omitempty" yaml:"controlPlaneEndpoint,omitempty"`
+	// RawConfig contains a complete Kind cluster configuration YAML, which is used instead of the structured fields
+	// The cluster name in the configuration is always replaced with the name managed by the provider
+	RawConfig string `json:"rawConfig,omitempty" yaml:"rawConfig,omitempty"`
+	// RawConfigRef references a ConfigMap key holding a Kind cluster configuration YAML, see RawConfig
+	RawConfigRef *KindClusterConfigMapKeyReference `json:"rawConfigRef,omitempty" yaml:"rawConfigRef,omitempty"`
+	// UpdateStrategy defines how changes of the Kind configuration are handled after the Kind cluster is created
+	// Immutable rejects the changes, Recreate deletes the Kind cluster and creates it again with the new configuration
+	UpdateStrategy KindClusterUpdateStrategy `json:"updateStrategy,omitempty" yaml:"updateStrategy,omitempty"`
+	// DeletionPolicy defines what happens when the KindCluster is deleted
+	// Delete deletes the Kind cluster and the owner Cluster, Orphan keeps the Kind cluster running and deletes the owner Cluster,
+	// Retain keeps the Kind cluster running and leaves the owner Cluster untouched
+	DeletionPolicy KindClusterDeletionPolicy `json:"deletionPolicy,omitempty" yaml:"deletionPolicy,omitempty"`
+	// RecoveryPolicy defines what happens when the running Kind cluster is lost, e.g. deleted outside of the provider
+	// Recreate creates the Kind cluster again, Fail marks the KindCluster as failed
+	RecoveryPolicy KindClusterRecoveryPolicy `json:"recoveryPolicy,omitempty" yaml:"recoveryPolicy,omitempty"`
+	// HostRef references the KindHost the Kind cluster is created on
+	// The Kind Wrapper API configured by the KIND_API_HOST environment variable of the manager is used if not set
+	HostRef *KindHostReference `json:"hostRef,omitempty" yaml:"hostRef,omitempty"`
+	// Scheduling configures the selection of a KindHost in case HostRef is not set
+	Scheduling *KindClusterScheduling `json:"scheduling,omitempty" yaml:"scheduling,omitempty"`
+	// Provisioning configures the timeout and retries of the creation of the Kind cluster
+	Provisioning *KindClusterProvisioning `json:"provisioning,omitempty" yaml:"provisioning,omitempty"`
+	// TTL is the time the KindCluster lives after its creation, the KindCluster and its owner Cluster are deleted once it expires
+	// The owner Cluster is kept by the Retain deletion policy
+	// The Kind Wrapper API deletes the Kind cluster by itself in case the KindCluster is not deleted in time, e.g. while the controller is down
+	TTL *metav1.Duration `json:"ttl,omitempty" yaml:"ttl,omitempty"`
 }
 
 // KindClusterStatus defines the observed state of KindCluster
@@ -115,10 +257,45 @@ type KindClusterStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 	State KindClusterState `json:"state,omitempty"`
 	Ready bool             `json:"ready,omitempty"`
+	// FailureReason is set when the Kind cluster ends up in a state that requires manual intervention
+	FailureReason *capierrors.ClusterStatusError `json:"failureReason,omitempty"`
+	// FailureMessage describes the failure in a human-readable form, usually as reported by the Kind Wrapper API
+	FailureMessage *string `json:"failureMessage,omitempty"`
+	// ObservedGeneration is the latest generation of the spec observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// OwnerHandedOver is set while the owner of the Kind cluster is recorded without the identity of the management cluster,
+	// because the KindCluster was paused, e.g. to be moved by clusterctl
+	OwnerHandedOver bool `json:"ownerHandedOver,omitempty"`
+	// AppliedSpecHash is a hash of the Kind configuration the Kind cluster was created with
+	AppliedSpecHash string `json:"appliedSpecHash,omitempty"`
+	// Host is the name of the KindHost the Kind cluster was created on, empty for the default Kind Wrapper API
+	Host string `json:"host,omitempty"`
+	// KindClusterName is the name of the Kind cluster, it is used for all calls of the Kind Wrapper API once recorded
+	KindClusterName string `json:"kindClusterName,omitempty"`
+	// Scheduling records the scheduling decision of a KindCluster without a host reference
+	Scheduling *KindClusterSchedulingStatus `json:"scheduling,omitempty"`
+	// Provisioning records the attempts to create the Kind cluster
+	Provisioning *KindClusterProvisioningStatus `json:"provisioning,omitempty"`
+	// ExpiresAt is the time the KindCluster expires according to its TTL and the extension annotation, not set without a TTL
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// RemainingTTL is the time left until the KindCluster expires, it is updated whenever the KindCluster is reconciled
+	RemainingTTL string `json:"remainingTTL,omitempty"`
+	// Conditions defines current service state of the KindCluster
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="State of the Kind cluster"
+//+kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready",description="Kind cluster is ready"
+//+kubebuilder:printcolumn:name="Provisioned",type="string",JSONPath=".status.conditions[?(@.type=='KindClusterProvisioned')].status",description="Kind cluster is provisioned"
+//+kubebuilder:printcolumn:name="Reachable",type="string",JSONPath=".status.conditions[?(@.type=='ControlPlaneReachable')].status",description="Control plane endpoint accepts connections"
+//+kubebuilder:printcolumn:name="Host",type="string",JSONPath=".status.host",description="KindHost the Kind cluster was created on",priority=1
+//+kubebuilder:printcolumn:name="Kind Name",type="string",JSONPath=".status.kindClusterName",description="Name of the Kind cluster",priority=1
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.failureReason",description="Reason of the failure"
+//+kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.failureMessage",description="Message describing the failure",priority=1
+//+kubebuilder:printcolumn:name="TTL",type="string",JSONPath=".status.remainingTTL",description="Time left until the KindCluster expires"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // KindCluster is the Schema for the kindclusters API
 type KindCluster struct {
@@ -159,6 +336,90 @@ func (k *KindCluster) AddControlPlaneEndpoint(host string, port int) {
 	k.Spec.ControlPlaneEndpoint = KindClusterControlPlaneEndpoint{Host: host, Port: port}
 }
 
+// HasRawConfig checks if the KindCluster object is configured by a raw Kind configuration
+func (k *KindCluster) HasRawConfig() bool {
+	return k.Spec.RawConfig != "" || k.Spec.RawConfigRef != nil
+}
+
+// IsAdopting checks if the KindCluster adopts an existing Kind cluster which has not been adopted yet
+func (k *KindCluster) IsAdopting() bool {
+	return k.Annotations[KindClusterAdoptAnnotation] != "" && k.Status.AppliedSpecHash == ""
+}
+
+// KeepsKindCluster checks if the Kind cluster is kept running when the KindCluster is deleted
+func (k *KindCluster) KeepsKindCluster() bool {
+	return k.Spec.DeletionPolicy == KindClusterDeletionPolicyOrphan || k.Spec.DeletionPolicy == KindClusterDeletionPolicyRetain
+}
+
+// HostName returns the name of the KindHost referenced by the spec, empty for the default Kind Wrapper API
+func (k *KindCluster) HostName() string {
+	if k.Spec.HostRef == nil {
+		return ""
+	}
+	return k.Spec.HostRef.Name
+}
+
+// ProvisioningTimeout returns the maximum duration of an attempt to create the Kind cluster
+func (k *KindCluster) ProvisioningTimeout() time.Duration {
+	if k.Spec.Provisioning == nil || k.Spec.Provisioning.Timeout == nil {
+		return DefaultProvisioningTimeout
+	}
+	return k.Spec.Provisioning.Timeout.Duration
+}
+
+// ProvisioningMaxRetries returns the number of times a failed attempt to create the Kind cluster is retried
+func (k *KindCluster) ProvisioningMaxRetries() int32 {
+	if k.Spec.Provisioning == nil || k.Spec.Provisioning.MaxRetries == nil {
+		return 0
+	}
+	return *k.Spec.Provisioning.MaxRetries
+}
+
+// ExpirationTime returns the time the KindCluster expires, i.e. the start of its TTL plus the TTL extended by the annotation
+// The TTL starts at the time recorded by the start annotation, or at the creation time of the KindCluster until it is recorded
+// Nil is returned without a TTL, an error is returned in case the extension is not a valid duration or the start not a valid time
+func (k *KindCluster) ExpirationTime() (*metav1.Time, error) {
+	if k.Spec.TTL == nil {
+		return nil, nil
+	}
+	start := k.CreationTimestamp.Time
+	if value, ok := k.Annotations[KindClusterTTLStartAnnotation]; ok {
+		recorded, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", KindClusterTTLStartAnnotation, err)
+		}
+		start = recorded
+	}
+	expiresAt := start.Add(k.Spec.TTL.Duration)
+	if value, ok := k.Annotations[KindClusterExtendTTLAnnotation]; ok {
+		extension, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", KindClusterExtendTTLAnnotation, err)
+		}
+		expiresAt = expiresAt.Add(extension)
+	}
+	expirationTime := metav1.NewTime(expiresAt)
+	return &expirationTime, nil
+}
+
+// GetConditions returns the conditions of the KindCluster
+func (k *KindCluster) GetConditions() clusterv1.Conditions {
+	return k.Status.Conditions
+}
+
+// SetConditions sets the conditions of the KindCluster
+func (k *KindCluster) SetConditions(conditions clusterv1.Conditions) {
+	k.Status.Conditions = conditions
+}
+
+// SetFailure marks the KindCluster as failed with the specified reason and message
+func (k *KindCluster) SetFailure(reason capierrors.ClusterStatusError, message string) {
+	k.Status.State = KindClusterStateFailed
+	k.Status.Ready = false
+	k.Status.FailureReason = &reason
+	k.Status.FailureMessage = &message
+}
+
 //+kubebuilder:object:root=true
 
 // KindClusterList contains a list of KindCluster
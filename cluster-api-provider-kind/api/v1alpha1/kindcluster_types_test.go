@@ -72,6 +72,45 @@ var _ = Describe("KindCluster", func() {
 			Expect(kindCluster.HasFinalizer(KindClusterFinalizerName)).To(BeFalse())
 		})
 
+		It("should compute the expiration time from the TTL and the extension annotation", func() {
+			created := metav1.NewTime(time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC))
+			kindCluster := &KindCluster{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created}}
+			expiresAt, err := kindCluster.ExpirationTime()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiresAt).To(BeNil())
+
+			kindCluster.Spec.TTL = &metav1.Duration{Duration: 2 * time.Hour}
+			expiresAt, err = kindCluster.ExpirationTime()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiresAt.Time).To(Equal(created.Add(2 * time.Hour)))
+
+			kindCluster.Annotations = map[string]string{KindClusterExtendTTLAnnotation: "30m"}
+			expiresAt, err = kindCluster.ExpirationTime()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiresAt.Time).To(Equal(created.Add(150 * time.Minute)))
+
+			kindCluster.Annotations[KindClusterExtendTTLAnnotation] = "1d"
+			_, err = kindCluster.ExpirationTime()
+			Expect(err).To(MatchError(ContainSubstring(KindClusterExtendTTLAnnotation)))
+		})
+
+		It("should compute the expiration time from the recorded start of the TTL", func() {
+			// A KindCluster moved by clusterctl is created again, the recorded start is kept
+			moved := metav1.NewTime(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+			kindCluster := &KindCluster{ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: moved,
+				Annotations:       map[string]string{KindClusterTTLStartAnnotation: "2023-05-01T10:00:00Z"},
+			}}
+			kindCluster.Spec.TTL = &metav1.Duration{Duration: 3 * time.Hour}
+			expiresAt, err := kindCluster.ExpirationTime()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiresAt.Time).To(Equal(time.Date(2023, 5, 1, 13, 0, 0, 0, time.UTC)))
+
+			kindCluster.Annotations[KindClusterTTLStartAnnotation] = "yesterday"
+			_, err = kindCluster.ExpirationTime()
+			Expect(err).To(MatchError(ContainSubstring(KindClusterTTLStartAnnotation)))
+		})
+
 		It("should serialize and deserialize specscorrectly", func() {
 			kindCluster := &KindCluster{
 				ObjectMeta: metav1.ObjectMeta{
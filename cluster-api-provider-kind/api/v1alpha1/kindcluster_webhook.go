@@ -0,0 +1,357 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"net"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"strings"
+	"time"
+)
+
+// log is for logging in this package.
+var kindclusterlog = logf.Log.WithName("kindcluster-resource")
+
+// SetupWebhookWithManager registers the KindCluster webhooks in the manager
+func (k *KindCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(k).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha1-kindcluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=kindclusters,verbs=create;update,versions=v1alpha1,name=mkindcluster.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &KindCluster{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+// Clusters configured by a raw Kind configuration are left untouched
+func (k *KindCluster) Default() {
+	kindclusterlog.Info("default", "name", k.Name)
+	if k.Spec.UpdateStrategy == "" {
+		k.Spec.UpdateStrategy = KindClusterUpdateStrategyImmutable
+	}
+	if k.Spec.DeletionPolicy == "" {
+		k.Spec.DeletionPolicy = KindClusterDeletionPolicyDelete
+	}
+	if k.Spec.RecoveryPolicy == "" {
+		k.Spec.RecoveryPolicy = KindClusterRecoveryPolicyRecreate
+	}
+	if k.HasRawConfig() {
+		return
+	}
+	if len(k.Spec.Nodes) == 0 {
+		k.Spec.Nodes = []KindClusterNode{{Role: KindClusterRoleControlPlane}}
+	}
+	for i := range k.Spec.Nodes {
+		node := &k.Spec.Nodes[i]
+		for j := range node.ExtraPortMappings {
+			if node.ExtraPortMappings[j].Protocol == "" {
+				node.ExtraPortMappings[j].Protocol = KindClusterProtocolTCP
+			}
+		}
+		for j := range node.ExtraMounts {
+			if node.ExtraMounts[j].Propagation == "" {
+				node.ExtraMounts[j].Propagation = KindClusterPropagationNone
+			}
+		}
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1alpha1-kindcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=kindclusters,verbs=create;update,versions=v1alpha1,name=vkindcluster.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &KindCluster{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (k *KindCluster) ValidateCreate() error {
+	kindclusterlog.Info("validate create", "name", k.Name)
+	return k.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (k *KindCluster) ValidateUpdate(old runtime.Object) error {
+	kindclusterlog.Info("validate update", "name", k.Name)
+	oldKindCluster, ok := old.(*KindCluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a KindCluster but got a %T", old))
+	}
+	return k.validate(k.validateUpdateStrategy(oldKindCluster)...)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (k *KindCluster) ValidateDelete() error {
+	return nil
+}
+
+func (k *KindCluster) validate(updateErrs ...*field.Error) error {
+	allErrs := append(field.ErrorList(updateErrs), k.validateRawConfig()...)
+	allErrs = append(allErrs, k.validateHostRef()...)
+	allErrs = append(allErrs, k.validateProvisioning()...)
+	allErrs = append(allErrs, k.validateTTL()...)
+	switch k.Spec.UpdateStrategy {
+	case "", KindClusterUpdateStrategyImmutable, KindClusterUpdateStrategyRecreate:
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("spec", "updateStrategy"), k.Spec.UpdateStrategy,
+			[]string{string(KindClusterUpdateStrategyImmutable), string(KindClusterUpdateStrategyRecreate)}))
+	}
+	switch k.Spec.DeletionPolicy {
+	case "", KindClusterDeletionPolicyDelete, KindClusterDeletionPolicyOrphan, KindClusterDeletionPolicyRetain:
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("spec", "deletionPolicy"), k.Spec.DeletionPolicy,
+			[]string{string(KindClusterDeletionPolicyDelete), string(KindClusterDeletionPolicyOrphan), string(KindClusterDeletionPolicyRetain)}))
+	}
+	switch k.Spec.RecoveryPolicy {
+	case "", KindClusterRecoveryPolicyRecreate, KindClusterRecoveryPolicyFail:
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("spec", "recoveryPolicy"), k.Spec.RecoveryPolicy,
+			[]string{string(KindClusterRecoveryPolicyRecreate), string(KindClusterRecoveryPolicyFail)}))
+	}
+	if !k.HasRawConfig() {
+		allErrs = append(allErrs, k.validateNodes()...)
+		allErrs = append(allErrs, k.validateNetworking()...)
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("KindCluster").GroupKind(), k.Name, allErrs)
+}
+
+// validateUpdateStrategy rejects changes of the Kind configuration and host unless the Recreate strategy is used
+// The old object is defaulted first, so that objects created before the defaulting webhook can still be updated
+// The spec of a KindCluster adopting a Kind cluster can be changed until the spec of the Kind cluster is recorded
+func (k *KindCluster) validateUpdateStrategy(old *KindCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	if k.Spec.UpdateStrategy == KindClusterUpdateStrategyRecreate || old.IsAdopting() {
+		return allErrs
+	}
+	defaulted := old.DeepCopy()
+	defaulted.Default()
+	if defaulted.Spec.KindConfigHash() != k.Spec.KindConfigHash() {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"),
+			"Kind configuration cannot be changed with the Immutable update strategy, use the Recreate strategy to recreate the Kind cluster"))
+	}
+	if defaulted.HostName() != k.HostName() {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "hostRef"),
+			"host cannot be changed with the Immutable update strategy, use the Recreate strategy to recreate the Kind cluster"))
+	}
+	return allErrs
+}
+
+func (k *KindCluster) validateRawConfig() field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	if !k.HasRawConfig() {
+		return allErrs
+	}
+	if k.Spec.HasStructuredConfig() {
+		allErrs = append(allErrs, field.Forbidden(specPath, "structured Kind configuration fields cannot be combined with rawConfig or rawConfigRef"))
+	}
+	if k.Spec.RawConfig != "" && k.Spec.RawConfigRef != nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("rawConfigRef"), "rawConfigRef cannot be combined with rawConfig"))
+	}
+	if k.Spec.RawConfig != "" {
+		if _, err := ParseKindConfig(k.Spec.RawConfig); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("rawConfig"), k.Spec.RawConfig, err.Error()))
+		}
+	}
+	if k.Spec.RawConfigRef != nil {
+		if k.Spec.RawConfigRef.Name == "" {
+			allErrs = append(allErrs, field.Required(specPath.Child("rawConfigRef", "name"), "ConfigMap name is required"))
+		}
+		if k.Spec.RawConfigRef.Key == "" {
+			allErrs = append(allErrs, field.Required(specPath.Child("rawConfigRef", "key"), "ConfigMap key is required"))
+		}
+	}
+	return allErrs
+}
+
+func (k *KindCluster) validateHostRef() field.ErrorList {
+	var allErrs field.ErrorList
+	if k.Spec.HostRef != nil && k.Spec.HostRef.Name == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "hostRef", "name"), "KindHost name is required"))
+	}
+	return allErrs
+}
+
+func (k *KindCluster) validateProvisioning() field.ErrorList {
+	var allErrs field.ErrorList
+	if k.Spec.Provisioning == nil {
+		return allErrs
+	}
+	provisioningPath := field.NewPath("spec", "provisioning")
+	if timeout := k.Spec.Provisioning.Timeout; timeout != nil && timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(provisioningPath.Child("timeout"), timeout.Duration.String(), "timeout must be positive"))
+	}
+	if maxRetries := k.Spec.Provisioning.MaxRetries; maxRetries != nil && *maxRetries < 0 {
+		allErrs = append(allErrs, field.Invalid(provisioningPath.Child("maxRetries"), *maxRetries, "maxRetries must not be negative"))
+	}
+	return allErrs
+}
+
+// validateTTL rejects a non-positive TTL, an extension annotation which is not a duration and a start annotation which is not a time
+func (k *KindCluster) validateTTL() field.ErrorList {
+	var allErrs field.ErrorList
+	if ttl := k.Spec.TTL; ttl != nil && ttl.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "ttl"), ttl.Duration.String(), "ttl must be positive"))
+	}
+	if value, ok := k.Annotations[KindClusterExtendTTLAnnotation]; ok {
+		if _, err := time.ParseDuration(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "annotations").Key(KindClusterExtendTTLAnnotation), value, "must be a duration, e.g. 2h"))
+		}
+	}
+	if value, ok := k.Annotations[KindClusterTTLStartAnnotation]; ok {
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "annotations").Key(KindClusterTTLStartAnnotation), value, "must be an RFC 3339 time, e.g. 2023-05-01T10:00:00Z"))
+		}
+	}
+	return allErrs
+}
+
+func (k *KindCluster) validateNodes() field.ErrorList {
+	var allErrs field.ErrorList
+	nodesPath := field.NewPath("spec", "nodes")
+	hasControlPlane := false
+	hostPorts := map[string][]boundHostPort{}
+	for i, node := range k.Spec.Nodes {
+		nodePath := nodesPath.Index(i)
+		switch node.Role {
+		case KindClusterRoleControlPlane:
+			hasControlPlane = true
+		case KindClusterRoleWorker:
+		default:
+			allErrs = append(allErrs, field.NotSupported(nodePath.Child("role"), node.Role,
+				[]string{string(KindClusterRoleControlPlane), string(KindClusterRoleWorker)}))
+		}
+		for j, mount := range node.ExtraMounts {
+			mountPath := nodePath.Child("extraMounts").Index(j)
+			switch mount.Propagation {
+			case "", KindClusterPropagationNone, KindClusterPropagationHostToContainer, KindClusterPropagationBidirectional:
+			default:
+				allErrs = append(allErrs, field.NotSupported(mountPath.Child("propagation"), mount.Propagation,
+					[]string{string(KindClusterPropagationNone), string(KindClusterPropagationHostToContainer), string(KindClusterPropagationBidirectional)}))
+			}
+		}
+		for j, mapping := range node.ExtraPortMappings {
+			mappingPath := nodePath.Child("extraPortMappings").Index(j)
+			protocol := mapping.Protocol
+			switch protocol {
+			case "":
+				protocol = KindClusterProtocolTCP
+			case KindClusterProtocolTCP, KindClusterProtocolUDP, KindClusterProtocolSCTP:
+			default:
+				allErrs = append(allErrs, field.NotSupported(mappingPath.Child("protocol"), mapping.Protocol,
+					[]string{string(KindClusterProtocolTCP), string(KindClusterProtocolUDP), string(KindClusterProtocolSCTP)}))
+			}
+			if mapping.ContainerPort < 0 || mapping.ContainerPort > 65535 {
+				allErrs = append(allErrs, field.Invalid(mappingPath.Child("containerPort"), mapping.ContainerPort, "must be between 0 and 65535"))
+			}
+			if mapping.HostPort < 0 || mapping.HostPort > 65535 {
+				allErrs = append(allErrs, field.Invalid(mappingPath.Child("hostPort"), mapping.HostPort, "must be between 0 and 65535"))
+			}
+			if mapping.ListenAddress != "" && net.ParseIP(mapping.ListenAddress) == nil {
+				allErrs = append(allErrs, field.Invalid(mappingPath.Child("listenAddress"), mapping.ListenAddress, "must be a valid IP address"))
+			}
+			// host port 0 is assigned randomly by Kind, so it can never collide
+			if mapping.HostPort == 0 {
+				continue
+			}
+			address := mapping.ListenAddress
+			if address == "" {
+				address = "0.0.0.0"
+			}
+			key := fmt.Sprintf("%d/%s", mapping.HostPort, protocol)
+			if previous := conflictingHostPort(hostPorts[key], address); previous != nil {
+				allErrs = append(allErrs, field.Duplicate(mappingPath.Child("hostPort"),
+					fmt.Sprintf("%s:%s is already mapped by %s", address, key, previous.String())))
+				continue
+			}
+			hostPorts[key] = append(hostPorts[key], boundHostPort{address: address, path: mappingPath})
+		}
+	}
+	if len(k.Spec.Nodes) > 0 && !hasControlPlane {
+		allErrs = append(allErrs, field.Invalid(nodesPath, len(k.Spec.Nodes), "at least one control-plane node is required"))
+	}
+	return allErrs
+}
+
+// boundHostPort is a host port mapped on a listen address by an extra port mapping
+type boundHostPort struct {
+	address string
+	path    *field.Path
+}
+
+// conflictingHostPort returns the path of a mapping already binding the same host port and protocol on the address
+// A host port bound on an unspecified address (Kind's default 0.0.0.0) conflicts with every other address
+func conflictingHostPort(bound []boundHostPort, address string) *field.Path {
+	for _, previous := range bound {
+		if previous.address == address || isUnspecifiedAddress(previous.address) || isUnspecifiedAddress(address) {
+			return previous.path
+		}
+	}
+	return nil
+}
+
+func isUnspecifiedAddress(address string) bool {
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsUnspecified()
+}
+
+func (k *KindCluster) validateNetworking() field.ErrorList {
+	var allErrs field.ErrorList
+	networking := k.Spec.Networking
+	networkingPath := field.NewPath("spec", "networking")
+	switch networking.IPFamily {
+	case "", KindCLusterIPFamilyV4, KindCLusterIPFamilyV6, KindClusterIPFamilyDual:
+	default:
+		allErrs = append(allErrs, field.NotSupported(networkingPath.Child("ipFamily"), networking.IPFamily,
+			[]string{string(KindCLusterIPFamilyV4), string(KindCLusterIPFamilyV6), string(KindClusterIPFamilyDual)}))
+	}
+	switch networking.KubeProxyMode {
+	case "", KindClusterKubeProxyModeNone, KindClusterKubeProxyModeIPTables, KindClusterKubeProxyModeIPVS:
+	default:
+		allErrs = append(allErrs, field.NotSupported(networkingPath.Child("kubeProxyMode"), networking.KubeProxyMode,
+			[]string{string(KindClusterKubeProxyModeNone), string(KindClusterKubeProxyModeIPTables), string(KindClusterKubeProxyModeIPVS)}))
+	}
+	if networking.APIServerAddress != "" && net.ParseIP(networking.APIServerAddress) == nil {
+		allErrs = append(allErrs, field.Invalid(networkingPath.Child("apiServerAddress"), networking.APIServerAddress, "must be a valid IP address"))
+	}
+	if networking.APIServerPort < 0 || networking.APIServerPort > 65535 {
+		allErrs = append(allErrs, field.Invalid(networkingPath.Child("apiServerPort"), networking.APIServerPort, "must be between 0 and 65535"))
+	}
+	allErrs = append(allErrs, validateSubnets(networkingPath.Child("podSubnet"), networking.PodSubnet)...)
+	allErrs = append(allErrs, validateSubnets(networkingPath.Child("serviceSubnet"), networking.ServiceSubnet)...)
+	return allErrs
+}
+
+// validateSubnets validates a subnet in CIDR notation, dual-stack clusters use a comma separated pair of subnets
+func validateSubnets(path *field.Path, subnets string) field.ErrorList {
+	var allErrs field.ErrorList
+	if subnets == "" {
+		return allErrs
+	}
+	for _, subnet := range strings.Split(subnets, ",") {
+		if _, _, err := net.ParseCIDR(strings.TrimSpace(subnet)); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, subnets, fmt.Sprintf("%q is not a valid CIDR", subnet)))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,423 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const rawKindConfig = `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+nodes:
+- role: control-plane
+`
+
+var _ = Describe("KindCluster webhook", func() {
+
+	var kindCluster *KindCluster
+
+	BeforeEach(func() {
+		kindCluster = &KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook-cluster",
+				Namespace: "default",
+			},
+		}
+	})
+
+	Context("Raw config", func() {
+		It("should accept either structured fields or raw config", func() {
+			kindCluster.Spec.Nodes = []KindClusterNode{{Role: KindClusterRoleControlPlane}}
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+
+			kindCluster.Spec = KindClusterSpec{RawConfig: rawKindConfig}
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+
+			kindCluster.Spec = KindClusterSpec{RawConfigRef: &KindClusterConfigMapKeyReference{Name: "kind-config", Key: "config.yaml"}}
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+			Expect(kindCluster.ValidateUpdate(kindCluster.DeepCopy())).To(Succeed())
+		})
+
+		It("should reject raw config combined with structured fields", func() {
+			kindCluster.Spec.RawConfig = rawKindConfig
+			kindCluster.Spec.Networking.APIServerAddress = "10.10.10.1"
+			err := kindCluster.ValidateCreate()
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(kindCluster.ValidateUpdate(kindCluster.DeepCopy())).NotTo(Succeed())
+
+			kindCluster.Spec = KindClusterSpec{
+				RawConfigRef: &KindClusterConfigMapKeyReference{Name: "kind-config", Key: "config.yaml"},
+				FeatureGates: map[string]bool{"EphemeralContainers": true},
+			}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should reject raw config combined with a raw config reference", func() {
+			kindCluster.Spec.RawConfig = rawKindConfig
+			kindCluster.Spec.RawConfigRef = &KindClusterConfigMapKeyReference{Name: "kind-config", Key: "config.yaml"}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should reject invalid raw config", func() {
+			kindCluster.Spec.RawConfig = rawKindConfig + "controlPlaneEndpoint: 10.10.10.1\n"
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+
+			kindCluster.Spec.RawConfig = "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha3\n"
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should reject incomplete raw config reference", func() {
+			kindCluster.Spec.RawConfigRef = &KindClusterConfigMapKeyReference{Name: "kind-config"}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+
+			kindCluster.Spec.RawConfigRef = &KindClusterConfigMapKeyReference{Key: "config.yaml"}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should reject invalid resources on create", func() {
+			kindCluster.Spec.RawConfig = rawKindConfig
+			kindCluster.Spec.Nodes = []KindClusterNode{{Role: KindClusterRoleControlPlane}}
+			err := k8sClient.Create(ctx, kindCluster)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+			kindCluster.Spec.Nodes = nil
+			Expect(k8sClient.Create(ctx, kindCluster)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, kindCluster)).To(Succeed())
+		})
+	})
+
+	Context("Defaulting", func() {
+		It("should add a control-plane node when no nodes are specified", func() {
+			kindCluster.Default()
+			Expect(kindCluster.Spec.Nodes).To(Equal([]KindClusterNode{{Role: KindClusterRoleControlPlane}}))
+		})
+
+		It("should default the port mapping protocol and mount propagation", func() {
+			kindCluster.Spec.Nodes = []KindClusterNode{{
+				Role:              KindClusterRoleControlPlane,
+				ExtraMounts:       []KindClusterExtraMount{{HostPath: "/tmp", ContainerPath: "/data"}, {Propagation: KindClusterPropagationBidirectional}},
+				ExtraPortMappings: []KindClusterExtraPortMapping{{ContainerPort: 80, HostPort: 8080}, {Protocol: KindClusterProtocolUDP}},
+			}}
+			kindCluster.Default()
+			Expect(kindCluster.Spec.Nodes).To(HaveLen(1))
+			Expect(kindCluster.Spec.Nodes[0].ExtraMounts[0].Propagation).To(Equal(KindClusterPropagationNone))
+			Expect(kindCluster.Spec.Nodes[0].ExtraMounts[1].Propagation).To(Equal(KindClusterPropagationBidirectional))
+			Expect(kindCluster.Spec.Nodes[0].ExtraPortMappings[0].Protocol).To(Equal(KindClusterProtocolTCP))
+			Expect(kindCluster.Spec.Nodes[0].ExtraPortMappings[1].Protocol).To(Equal(KindClusterProtocolUDP))
+		})
+
+		It("should not default clusters configured by a raw config", func() {
+			kindCluster.Spec.RawConfig = rawKindConfig
+			kindCluster.Default()
+			Expect(kindCluster.Spec.Nodes).To(BeEmpty())
+		})
+
+		It("should default the update strategy to Immutable", func() {
+			kindCluster.Default()
+			Expect(kindCluster.Spec.UpdateStrategy).To(Equal(KindClusterUpdateStrategyImmutable))
+
+			kindCluster.Spec = KindClusterSpec{RawConfig: rawKindConfig, UpdateStrategy: KindClusterUpdateStrategyRecreate}
+			kindCluster.Default()
+			Expect(kindCluster.Spec.UpdateStrategy).To(Equal(KindClusterUpdateStrategyRecreate))
+		})
+
+		It("should default the deletion policy to Delete", func() {
+			kindCluster.Default()
+			Expect(kindCluster.Spec.DeletionPolicy).To(Equal(KindClusterDeletionPolicyDelete))
+
+			kindCluster.Spec.DeletionPolicy = KindClusterDeletionPolicyRetain
+			kindCluster.Default()
+			Expect(kindCluster.Spec.DeletionPolicy).To(Equal(KindClusterDeletionPolicyRetain))
+		})
+
+		It("should default the recovery policy to Recreate", func() {
+			kindCluster.Default()
+			Expect(kindCluster.Spec.RecoveryPolicy).To(Equal(KindClusterRecoveryPolicyRecreate))
+
+			kindCluster.Spec.RecoveryPolicy = KindClusterRecoveryPolicyFail
+			kindCluster.Default()
+			Expect(kindCluster.Spec.RecoveryPolicy).To(Equal(KindClusterRecoveryPolicyFail))
+		})
+
+		It("should default resources on create", func() {
+			kindCluster.Name = "webhook-defaulted-cluster"
+			Expect(k8sClient.Create(ctx, kindCluster)).To(Succeed())
+			Expect(kindCluster.Spec.Nodes).To(Equal([]KindClusterNode{{Role: KindClusterRoleControlPlane}}))
+			Expect(k8sClient.Delete(ctx, kindCluster)).To(Succeed())
+		})
+	})
+
+	Context("Validation", func() {
+		BeforeEach(func() {
+			kindCluster.Spec.Nodes = []KindClusterNode{{Role: KindClusterRoleControlPlane}, {Role: KindClusterRoleWorker}}
+		})
+
+		It("should accept a valid spec", func() {
+			kindCluster.Spec.Networking = KindClusterNetworking{
+				IPFamily:         KindClusterIPFamilyDual,
+				APIServerAddress: "127.0.0.1",
+				APIServerPort:    6443,
+				PodSubnet:        "10.244.0.0/16,fd00:10:244::/56",
+				ServiceSubnet:    "10.96.0.0/12",
+				KubeProxyMode:    KindClusterKubeProxyModeIPVS,
+			}
+			kindCluster.Spec.Nodes[0].ExtraPortMappings = []KindClusterExtraPortMapping{
+				{ContainerPort: 80, HostPort: 8080},
+				{ContainerPort: 80, HostPort: 8080, Protocol: KindClusterProtocolUDP},
+				{ContainerPort: 443},
+			}
+			kindCluster.Spec.Nodes[1].ExtraPortMappings = []KindClusterExtraPortMapping{{ContainerPort: 443}}
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject a node list without a control-plane node", func() {
+			kindCluster.Spec.Nodes = []KindClusterNode{{Role: KindClusterRoleWorker}}
+			Expect(apierrors.IsInvalid(kindCluster.ValidateCreate())).To(BeTrue())
+		})
+
+		It("should reject an unknown node role", func() {
+			kindCluster.Spec.Nodes[1].Role = "master"
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should reject duplicate host ports", func() {
+			kindCluster.Spec.Nodes[0].ExtraPortMappings = []KindClusterExtraPortMapping{{ContainerPort: 80, HostPort: 8080}}
+			kindCluster.Spec.Nodes[1].ExtraPortMappings = []KindClusterExtraPortMapping{{ContainerPort: 8080, HostPort: 8080, Protocol: KindClusterProtocolTCP}}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should compare host ports per listen address", func() {
+			kindCluster.Spec.Nodes[0].ExtraPortMappings = []KindClusterExtraPortMapping{
+				{ContainerPort: 80, HostPort: 8080, ListenAddress: "127.0.0.1"},
+				{ContainerPort: 80, HostPort: 8080, ListenAddress: "127.0.0.2"},
+			}
+			kindCluster.Spec.Nodes[1].ExtraPortMappings = []KindClusterExtraPortMapping{{ContainerPort: 80, HostPort: 8080, ListenAddress: "::1"}}
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+
+			kindCluster.Spec.Nodes[1].ExtraPortMappings = []KindClusterExtraPortMapping{{ContainerPort: 80, HostPort: 8080, ListenAddress: "127.0.0.2"}}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+
+			// the default listen address binds all addresses
+			kindCluster.Spec.Nodes[1].ExtraPortMappings = []KindClusterExtraPortMapping{{ContainerPort: 80, HostPort: 8080}}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should reject invalid port mappings and mounts", func() {
+			kindCluster.Spec.Nodes[0].ExtraPortMappings = []KindClusterExtraPortMapping{{ContainerPort: 80, HostPort: 70000}}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+
+			kindCluster.Spec.Nodes[0].ExtraPortMappings = []KindClusterExtraPortMapping{{ContainerPort: 80, Protocol: "HTTP"}}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+
+			kindCluster.Spec.Nodes[0].ExtraPortMappings = []KindClusterExtraPortMapping{{ContainerPort: 80, ListenAddress: "localhost"}}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+
+			kindCluster.Spec.Nodes[0].ExtraPortMappings = nil
+			kindCluster.Spec.Nodes[0].ExtraMounts = []KindClusterExtraMount{{HostPath: "/tmp", ContainerPath: "/data", Propagation: "Shared"}}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should reject an unknown kube-proxy mode and IP family", func() {
+			kindCluster.Spec.Networking.KubeProxyMode = "nftables"
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+
+			kindCluster.Spec.Networking = KindClusterNetworking{IPFamily: "ipv5"}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should reject malformed subnets and API server address", func() {
+			kindCluster.Spec.Networking.PodSubnet = "10.244.0.0"
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+
+			kindCluster.Spec.Networking = KindClusterNetworking{ServiceSubnet: "10.96.0.0/12,fd00::/400"}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+
+			kindCluster.Spec.Networking = KindClusterNetworking{APIServerAddress: "10.10.10"}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should reject invalid resources on create and update", func() {
+			kindCluster.Name = "webhook-validated-cluster"
+			kindCluster.Spec.Nodes = []KindClusterNode{{Role: KindClusterRoleWorker}}
+			Expect(apierrors.IsInvalid(k8sClient.Create(ctx, kindCluster))).To(BeTrue())
+
+			kindCluster.Spec.Nodes = nil
+			Expect(k8sClient.Create(ctx, kindCluster)).To(Succeed())
+			kindCluster.Spec.Networking.KubeProxyMode = "nftables"
+			Expect(apierrors.IsInvalid(k8sClient.Update(ctx, kindCluster))).To(BeTrue())
+			Expect(k8sClient.Delete(ctx, kindCluster)).To(Succeed())
+		})
+	})
+
+	Context("Update strategy", func() {
+		var old *KindCluster
+
+		BeforeEach(func() {
+			kindCluster.Spec.Nodes = []KindClusterNode{{Role: KindClusterRoleControlPlane}}
+			kindCluster.Default()
+			old = kindCluster.DeepCopy()
+		})
+
+		It("should reject Kind config changes with the Immutable strategy", func() {
+			kindCluster.Spec.Nodes = append(kindCluster.Spec.Nodes, KindClusterNode{Role: KindClusterRoleWorker})
+			Expect(apierrors.IsInvalid(kindCluster.ValidateUpdate(old))).To(BeTrue())
+
+			kindCluster.Spec = *old.Spec.DeepCopy()
+			kindCluster.Spec.RawConfigRef = &KindClusterConfigMapKeyReference{Name: "kind-config", Key: "config.yaml"}
+			kindCluster.Spec.Nodes = nil
+			Expect(kindCluster.ValidateUpdate(old)).NotTo(Succeed())
+		})
+
+		It("should reject host changes with the Immutable strategy", func() {
+			kindCluster.Spec.HostRef = &KindHostReference{Name: "lab-01"}
+			Expect(kindCluster.ValidateUpdate(old)).NotTo(Succeed())
+
+			kindCluster.Spec.UpdateStrategy = KindClusterUpdateStrategyRecreate
+			Expect(kindCluster.ValidateUpdate(old)).To(Succeed())
+		})
+
+		It("should reject a host reference without a name", func() {
+			kindCluster.Spec.HostRef = &KindHostReference{}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should allow changes not affecting the Kind config with the Immutable strategy", func() {
+			kindCluster.Spec.ControlPlaneEndpoint = KindClusterControlPlaneEndpoint{Host: "127.0.0.1", Port: 6443}
+			Expect(kindCluster.ValidateUpdate(old)).To(Succeed())
+
+			kindCluster.Spec.UpdateStrategy = KindClusterUpdateStrategyRecreate
+			Expect(kindCluster.ValidateUpdate(old)).To(Succeed())
+		})
+
+		It("should allow updates of objects created before defaulting", func() {
+			old.Spec = KindClusterSpec{}
+			Expect(kindCluster.ValidateUpdate(old)).To(Succeed())
+		})
+
+		It("should allow Kind config changes until an adopted Kind cluster is recorded", func() {
+			old.Annotations = map[string]string{KindClusterAdoptAnnotation: "hand-made"}
+			kindCluster.Spec.Nodes = append(kindCluster.Spec.Nodes, KindClusterNode{Role: KindClusterRoleWorker})
+			Expect(kindCluster.ValidateUpdate(old)).To(Succeed())
+
+			old.Status.AppliedSpecHash = old.Spec.KindConfigHash()
+			Expect(kindCluster.ValidateUpdate(old)).NotTo(Succeed())
+		})
+
+		It("should allow Kind config changes with the Recreate strategy", func() {
+			kindCluster.Spec.UpdateStrategy = KindClusterUpdateStrategyRecreate
+			kindCluster.Spec.Nodes = append(kindCluster.Spec.Nodes, KindClusterNode{Role: KindClusterRoleWorker})
+			Expect(kindCluster.ValidateUpdate(old)).To(Succeed())
+		})
+
+		It("should reject an unknown deletion policy", func() {
+			kindCluster.Spec.DeletionPolicy = "Keep"
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+			kindCluster.Spec.DeletionPolicy = KindClusterDeletionPolicyOrphan
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject an unknown recovery policy", func() {
+			kindCluster.Spec.RecoveryPolicy = "Ignore"
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+			kindCluster.Spec.RecoveryPolicy = KindClusterRecoveryPolicyFail
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject a non-positive provisioning timeout", func() {
+			kindCluster.Spec.Provisioning = &KindClusterProvisioning{Timeout: &metav1.Duration{}}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+			kindCluster.Spec.Provisioning.Timeout = &metav1.Duration{Duration: time.Minute}
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject a non-positive TTL, an invalid TTL extension and an invalid TTL start", func() {
+			kindCluster.Spec.TTL = &metav1.Duration{}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+			kindCluster.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+
+			kindCluster.Annotations = map[string]string{KindClusterExtendTTLAnnotation: "1d"}
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+			kindCluster.Annotations[KindClusterExtendTTLAnnotation] = "24h"
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+
+			kindCluster.Annotations[KindClusterTTLStartAnnotation] = "yesterday"
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+			kindCluster.Annotations[KindClusterTTLStartAnnotation] = "2023-05-01T10:00:00Z"
+			Expect(kindCluster.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject an unknown update strategy", func() {
+			kindCluster.Spec.UpdateStrategy = "Rolling"
+			Expect(kindCluster.ValidateCreate()).NotTo(Succeed())
+		})
+
+		It("should reject Kind config changes on update", func() {
+			kindCluster.Name = "webhook-immutable-cluster"
+			Expect(k8sClient.Create(ctx, kindCluster)).To(Succeed())
+			kindCluster.Spec.Networking.KubeProxyMode = KindClusterKubeProxyModeIPVS
+			Expect(apierrors.IsInvalid(k8sClient.Update(ctx, kindCluster))).To(BeTrue())
+
+			kindCluster.Spec.UpdateStrategy = KindClusterUpdateStrategyRecreate
+			Expect(k8sClient.Update(ctx, kindCluster)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, kindCluster)).To(Succeed())
+		})
+	})
+
+	Context("Cluster templates", func() {
+		It("should accept the KindClusters of all clusterctl flavors", func() {
+			templates, err := filepath.Glob(filepath.Join("..", "..", "templates", "cluster-template*.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(templates).To(HaveLen(3))
+
+			for i, template := range templates {
+				content, err := os.ReadFile(template)
+				Expect(err).NotTo(HaveOccurred())
+				// KIND_API_SERVER_ADDRESS is left out, so that its default is used like by clusterctl
+				variables := map[string]string{
+					"CLUSTER_NAME":       fmt.Sprintf("template-cluster-%d", i),
+					"NAMESPACE":          "default",
+					"KUBERNETES_VERSION": "v1.26.3",
+				}
+				content = []byte(os.Expand(string(content), func(name string) string {
+					if parts := strings.SplitN(name, ":=", 2); len(parts) == 2 {
+						if value, ok := variables[parts[0]]; ok {
+							return value
+						}
+						return parts[1]
+					}
+					return variables[name]
+				}))
+
+				decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+				kindClusters := 0
+				for {
+					var object KindCluster
+					if err := decoder.Decode(&object); errors.Is(err, io.EOF) {
+						break
+					} else {
+						Expect(err).NotTo(HaveOccurred(), template)
+					}
+					if object.Kind != "KindCluster" {
+						continue
+					}
+					kindClusters++
+					Expect(object.Spec.Networking.APIServerAddress).To(Equal("127.0.0.1"), template)
+					Expect(k8sClient.Create(ctx, &object)).To(Succeed(), template)
+					Expect(k8sClient.Delete(ctx, &object)).To(Succeed())
+				}
+				Expect(kindClusters).To(Equal(1), template)
+			}
+		})
+	})
+})
@@ -0,0 +1,147 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// KindHostCredentialsTokenKey is the key of the bearer token in the KindHost credentials Secret
+	KindHostCredentialsTokenKey = "token"
+)
+
+// KindHostSecretReference references a Secret holding the credentials of a Kind Wrapper API
+// The Secret is read from the namespace of the provider, KindHosts are cluster-scoped and must not reach Secrets of other namespaces
+type KindHostSecretReference struct {
+	Name string `json:"name"`
+}
+
+// KindHostCapacity defines limits of the Kind clusters which can be created on a host, unset fields are not limited
+type KindHostCapacity struct {
+	// MaxClusters is the maximum number of Kind clusters on the host
+	MaxClusters *int32 `json:"maxClusters,omitempty"`
+	// MaxNodes is the maximum number of Kind nodes of all Kind clusters on the host
+	MaxNodes *int32 `json:"maxNodes,omitempty"`
+}
+
+// KindHostSpec defines the desired state of KindHost
+type KindHostSpec struct {
+	// URL of the Kind Wrapper API running on the host, e.g. https://lab-01.example.com:8888
+	//+kubebuilder:validation:Pattern=`^https?://.+`
+	URL string `json:"url"`
+	// CredentialsSecretRef references a Secret in the namespace of the provider with a bearer token of the Kind Wrapper API under the "token" key
+	CredentialsSecretRef *KindHostSecretReference `json:"credentialsSecretRef,omitempty"`
+	// CABundle is a PEM encoded CA bundle used to verify the certificate of the Kind Wrapper API
+	CABundle []byte `json:"caBundle,omitempty"`
+	// Capacity limits the Kind clusters created on the host
+	Capacity KindHostCapacity `json:"capacity,omitempty"`
+}
+
+// KindHostResource contains the total and free amount of a resource of the host
+type KindHostResource struct {
+	Total resource.Quantity `json:"total"`
+	Free  resource.Quantity `json:"free"`
+}
+
+// KindHostResources contains the resources of the host reported by the Kind Wrapper API
+type KindHostResources struct {
+	CPU    KindHostResource `json:"cpu"`
+	Memory KindHostResource `json:"memory"`
+	Disk   KindHostResource `json:"disk"`
+}
+
+// KindHostStatus defines the observed state of KindHost
+type KindHostStatus struct {
+	// Resources reported by the Kind Wrapper API, not set until the first successful report
+	Resources *KindHostResources `json:"resources,omitempty"`
+	// Clusters is the number of Kind clusters created on or scheduled onto the host by the provider
+	Clusters int32 `json:"clusters,omitempty"`
+	// Nodes is the number of Kind nodes of the Kind clusters created on or scheduled onto the host by the provider
+	Nodes int32 `json:"nodes,omitempty"`
+	// RuntimeVersion is the version of the container runtime running the Kind nodes
+	RuntimeVersion string `json:"runtimeVersion,omitempty"`
+	// KindVersion is the version of Kind used by the Kind Wrapper API
+	KindVersion string `json:"kindVersion,omitempty"`
+	// Features lists the features supported by the Kind Wrapper API
+	Features []string `json:"features,omitempty"`
+	// LastReportTime is the time of the last successful report of the Kind Wrapper API
+	LastReportTime *metav1.Time `json:"lastReportTime,omitempty"`
+	// Conditions defines current service state of the KindHost
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.url",description="URL of the Kind Wrapper API"
+//+kubebuilder:printcolumn:name="Max Clusters",type="integer",JSONPath=".spec.capacity.maxClusters",description="Maximum number of Kind clusters"
+//+kubebuilder:printcolumn:name="Max Nodes",type="integer",JSONPath=".spec.capacity.maxNodes",description="Maximum number of Kind nodes"
+//+kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type=='KindHostAvailable')].status",description="Kind Wrapper API is available"
+//+kubebuilder:printcolumn:name="Clusters",type="integer",JSONPath=".status.clusters",description="Number of Kind clusters"
+//+kubebuilder:printcolumn:name="Kind Version",type="string",JSONPath=".status.kindVersion",description="Version of Kind",priority=1
+//+kubebuilder:printcolumn:name="Free CPU",type="string",JSONPath=".status.resources.cpu.free",description="Free CPU",priority=1
+//+kubebuilder:printcolumn:name="Free Memory",type="string",JSONPath=".status.resources.memory.free",description="Free memory",priority=1
+//+kubebuilder:printcolumn:name="Free Disk",type="string",JSONPath=".status.resources.disk.free",description="Free disk",priority=1
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// KindHost is the Schema for the kindhosts API
+// It describes a machine running the Kind Wrapper API, which KindClusters can be created on
+type KindHost struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KindHostSpec   `json:"spec,omitempty"`
+	Status KindHostStatus `json:"status,omitempty"`
+}
+
+// HasCapacityFor checks if the specified number of clusters and nodes fits into the capacity of the host
+func (h *KindHost) HasCapacityFor(clusters, nodes int) bool {
+	capacity := h.Spec.Capacity
+	if capacity.MaxClusters != nil && clusters > int(*capacity.MaxClusters) {
+		return false
+	}
+	if capacity.MaxNodes != nil && nodes > int(*capacity.MaxNodes) {
+		return false
+	}
+	return true
+}
+
+// GetConditions returns the conditions of the KindHost
+func (h *KindHost) GetConditions() clusterv1.Conditions {
+	return h.Status.Conditions
+}
+
+// SetConditions sets the conditions of the KindHost
+func (h *KindHost) SetConditions(conditions clusterv1.Conditions) {
+	h.Status.Conditions = conditions
+}
+
+//+kubebuilder:object:root=true
+
+// KindHostList contains a list of KindHost
+type KindHostList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KindHost `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KindHost{}, &KindHostList{})
+}
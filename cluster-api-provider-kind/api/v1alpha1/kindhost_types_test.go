@@ -0,0 +1,20 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KindHost", func() {
+
+	It("should check the capacity of the host", func() {
+		host := &KindHost{}
+		Expect(host.HasCapacityFor(100, 1000)).To(BeTrue())
+
+		maxClusters, maxNodes := int32(2), int32(4)
+		host.Spec.Capacity = KindHostCapacity{MaxClusters: &maxClusters, MaxNodes: &maxNodes}
+		Expect(host.HasCapacityFor(2, 4)).To(BeTrue())
+		Expect(host.HasCapacityFor(3, 3)).To(BeFalse())
+		Expect(host.HasCapacityFor(1, 5)).To(BeFalse())
+	})
+})
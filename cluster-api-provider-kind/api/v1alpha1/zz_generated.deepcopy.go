@@ -22,7 +22,11 @@ limitations under the License.
 package v1alpha1
 
 import (
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/errors"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -31,7 +35,7 @@ func (in *KindCluster) DeepCopyInto(out *KindCluster) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindCluster.
@@ -52,6 +56,21 @@ func (in *KindCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterConfigMapKeyReference) DeepCopyInto(out *KindClusterConfigMapKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterConfigMapKeyReference.
+func (in *KindClusterConfigMapKeyReference) DeepCopy() *KindClusterConfigMapKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterConfigMapKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KindClusterControlPlaneEndpoint) DeepCopyInto(out *KindClusterControlPlaneEndpoint) {
 	*out = *in
@@ -132,6 +151,15 @@ func (in *KindClusterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KindClusterNetworking) DeepCopyInto(out *KindClusterNetworking) {
 	*out = *in
+	if in.DNSSearch != nil {
+		in, out := &in.DNSSearch, &out.DNSSearch
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterNetworking.
@@ -147,11 +175,23 @@ func (in *KindClusterNetworking) DeepCopy() *KindClusterNetworking {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KindClusterNode) DeepCopyInto(out *KindClusterNode) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.KubeAdmConfigPatches != nil {
 		in, out := &in.KubeAdmConfigPatches, &out.KubeAdmConfigPatches
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.KubeAdmConfigPatchesJSON6902 != nil {
+		in, out := &in.KubeAdmConfigPatchesJSON6902, &out.KubeAdmConfigPatchesJSON6902
+		*out = make([]KindClusterPatchJSON6902, len(*in))
+		copy(*out, *in)
+	}
 	if in.ExtraMounts != nil {
 		in, out := &in.ExtraMounts, &out.ExtraMounts
 		*out = make([]KindClusterExtraMount, len(*in))
@@ -174,6 +214,117 @@ func (in *KindClusterNode) DeepCopy() *KindClusterNode {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterPatchJSON6902) DeepCopyInto(out *KindClusterPatchJSON6902) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterPatchJSON6902.
+func (in *KindClusterPatchJSON6902) DeepCopy() *KindClusterPatchJSON6902 {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterPatchJSON6902)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterProvisioning) DeepCopyInto(out *KindClusterProvisioning) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterProvisioning.
+func (in *KindClusterProvisioning) DeepCopy() *KindClusterProvisioning {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterProvisioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterProvisioningStatus) DeepCopyInto(out *KindClusterProvisioningStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextAttemptTime != nil {
+		in, out := &in.NextAttemptTime, &out.NextAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterProvisioningStatus.
+func (in *KindClusterProvisioningStatus) DeepCopy() *KindClusterProvisioningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterProvisioningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterScheduling) DeepCopyInto(out *KindClusterScheduling) {
+	*out = *in
+	if in.HostSelector != nil {
+		in, out := &in.HostSelector, &out.HostSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeResources != nil {
+		in, out := &in.NodeResources, &out.NodeResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterScheduling.
+func (in *KindClusterScheduling) DeepCopy() *KindClusterScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterSchedulingStatus) DeepCopyInto(out *KindClusterSchedulingStatus) {
+	*out = *in
+	in.LastScheduleTime.DeepCopyInto(&out.LastScheduleTime)
+	if in.FailedHosts != nil {
+		in, out := &in.FailedHosts, &out.FailedHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterSchedulingStatus.
+func (in *KindClusterSchedulingStatus) DeepCopy() *KindClusterSchedulingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterSchedulingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KindClusterSpec) DeepCopyInto(out *KindClusterSpec) {
 	*out = *in
@@ -191,7 +342,7 @@ func (in *KindClusterSpec) DeepCopyInto(out *KindClusterSpec) {
 			(*out)[key] = val
 		}
 	}
-	out.Networking = in.Networking
+	in.Networking.DeepCopyInto(&out.Networking)
 	if in.Nodes != nil {
 		in, out := &in.Nodes, &out.Nodes
 		*out = make([]KindClusterNode, len(*in))
@@ -199,7 +350,52 @@ func (in *KindClusterSpec) DeepCopyInto(out *KindClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.KubeAdmConfigPatches != nil {
+		in, out := &in.KubeAdmConfigPatches, &out.KubeAdmConfigPatches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KubeAdmConfigPatchesJSON6902 != nil {
+		in, out := &in.KubeAdmConfigPatchesJSON6902, &out.KubeAdmConfigPatchesJSON6902
+		*out = make([]KindClusterPatchJSON6902, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContainerdConfigPatches != nil {
+		in, out := &in.ContainerdConfigPatches, &out.ContainerdConfigPatches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContainerdConfigPatchesJSON6902 != nil {
+		in, out := &in.ContainerdConfigPatchesJSON6902, &out.ContainerdConfigPatchesJSON6902
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.RawConfigRef != nil {
+		in, out := &in.RawConfigRef, &out.RawConfigRef
+		*out = new(KindClusterConfigMapKeyReference)
+		**out = **in
+	}
+	if in.HostRef != nil {
+		in, out := &in.HostRef, &out.HostRef
+		*out = new(KindHostReference)
+		**out = **in
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(KindClusterScheduling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Provisioning != nil {
+		in, out := &in.Provisioning, &out.Provisioning
+		*out = new(KindClusterProvisioning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterSpec.
@@ -215,6 +411,37 @@ func (in *KindClusterSpec) DeepCopy() *KindClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KindClusterStatus) DeepCopyInto(out *KindClusterStatus) {
 	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.ClusterStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(KindClusterSchedulingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Provisioning != nil {
+		in, out := &in.Provisioning, &out.Provisioning
+		*out = new(KindClusterProvisioningStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterStatus.
@@ -226,3 +453,214 @@ func (in *KindClusterStatus) DeepCopy() *KindClusterStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHost) DeepCopyInto(out *KindHost) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHost.
+func (in *KindHost) DeepCopy() *KindHost {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindHost) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostCapacity) DeepCopyInto(out *KindHostCapacity) {
+	*out = *in
+	if in.MaxClusters != nil {
+		in, out := &in.MaxClusters, &out.MaxClusters
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNodes != nil {
+		in, out := &in.MaxNodes, &out.MaxNodes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostCapacity.
+func (in *KindHostCapacity) DeepCopy() *KindHostCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostList) DeepCopyInto(out *KindHostList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KindHost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostList.
+func (in *KindHostList) DeepCopy() *KindHostList {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindHostList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostReference) DeepCopyInto(out *KindHostReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostReference.
+func (in *KindHostReference) DeepCopy() *KindHostReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostResource) DeepCopyInto(out *KindHostResource) {
+	*out = *in
+	out.Total = in.Total.DeepCopy()
+	out.Free = in.Free.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostResource.
+func (in *KindHostResource) DeepCopy() *KindHostResource {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostResources) DeepCopyInto(out *KindHostResources) {
+	*out = *in
+	in.CPU.DeepCopyInto(&out.CPU)
+	in.Memory.DeepCopyInto(&out.Memory)
+	in.Disk.DeepCopyInto(&out.Disk)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostResources.
+func (in *KindHostResources) DeepCopy() *KindHostResources {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostSecretReference) DeepCopyInto(out *KindHostSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostSecretReference.
+func (in *KindHostSecretReference) DeepCopy() *KindHostSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostSpec) DeepCopyInto(out *KindHostSpec) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(KindHostSecretReference)
+		**out = **in
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	in.Capacity.DeepCopyInto(&out.Capacity)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostSpec.
+func (in *KindHostSpec) DeepCopy() *KindHostSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostStatus) DeepCopyInto(out *KindHostStatus) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(KindHostResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastReportTime != nil {
+		in, out := &in.LastReportTime, &out.LastReportTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostStatus.
+func (in *KindHostStatus) DeepCopy() *KindHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -3,25 +3,77 @@ package controllers
 import (
 	"bytes"
 	"cluster-api-provider-kind/api/v1alpha1"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"gopkg.in/yaml.v3"
+	"io"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"net/http"
+	neturl "net/url"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // KindState defines Kind cluster states, which can be obtained from the Kind Wrapper API
 type KindState string
 
 const (
-	kindAPIHostEnvName = "KIND_API_HOST"
-	kindAPIDefaultHost = "http://127.0.0.1:8888"
+	kindAPIHostEnvName  = "KIND_API_HOST"
+	kindAPITokenEnvName = "KIND_API_TOKEN"
+	kindAPIDefaultHost  = "http://127.0.0.1:8888"
 
-	kindApiPathCluster = "/api/v1/cluster"
+	// kindApiCreateTimeout is longer than the other timeouts, because the Kind Wrapper API responds once the Kind cluster gets created
+	kindApiRequestTimeout = 10 * time.Second
+	kindApiCreateTimeout  = 2 * time.Minute
+	kindApiRetryInterval  = 200 * time.Millisecond
+	kindApiRetryAttempts  = 3
 
-	kindClusterKind       = "Cluster"
-	kindClusterAPIVersion = "kind.x-k8s.io/v1alpha4"
+	// kindClusterNameMaxLength keeps the names of the node containers, e.g. <name>-external-load-balancer, within the 63 characters of a hostname
+	kindClusterNameMaxLength  = 40
+	kindClusterNameHashLength = 8
+
+	kindApiPathHealth       = "/health"
+	kindApiPathCluster      = "/api/v1/cluster"
+	kindApiPathDetails      = "/details"
+	kindApiPathHost         = "/api/v1/host"
+	kindApiPathCapabilities = "/api/v1/capabilities"
+	kindApiPathClusters     = "/api/v1/clusters"
+	kindApiPathOwner        = "/owner"
+	kindApiPathTTL          = "/ttl"
+	kindApiParamOwner       = "owner"
+	kindApiParamManager     = "manager"
+	kindApiParamLabel       = "label"
+	kindApiParamTTL         = "ttl"
+
+	// The query parameters filtering and paginating the listed Kind clusters and the header with the next page
+	kindApiParamLabelSelector = "labelSelector"
+	kindApiParamState         = "state"
+	kindApiParamOlderThan     = "olderThan"
+	kindApiParamLimit         = "limit"
+	kindApiParamContinue      = "continue"
+	kindApiHeaderContinue     = "X-Continue"
+
+	// The endpoints of the Kind Wrapper API reported by the request metric
+	kindApiEndpointCreateCluster  = "create_cluster"
+	kindApiEndpointDeleteCluster  = "delete_cluster"
+	kindApiEndpointClusterStatus  = "cluster_status"
+	kindApiEndpointClusterDetails = "cluster_details"
+	kindApiEndpointHealth         = "health"
+	kindApiEndpointHost           = "host"
+	kindApiEndpointCapabilities   = "capabilities"
+	kindApiEndpointListClusters   = "list_clusters"
+	kindApiEndpointClusterOwner   = "cluster_owner"
+	kindApiEndpointClusterTTL     = "cluster_ttl"
 
 	KindStatePending = KindState("pending")
 	KindStateRunning = KindState("running")
@@ -31,61 +83,235 @@ const (
 // KindClusterNotFoundError is returned when a Kind cluster does not exist
 var KindClusterNotFoundError = errors.New("kind cluster not found")
 
+// KindClusterInvalidConfigError is returned when the Kind Wrapper API rejects a Kind cluster configuration
+var KindClusterInvalidConfigError = errors.New("kind cluster config rejected by kind api")
+
+// KindCapabilitiesNotSupportedError is returned when the Kind Wrapper API does not report its capabilities
+var KindCapabilitiesNotSupportedError = errors.New("kind api does not report capabilities")
+
+// KindOwnershipNotSupportedError is returned when the Kind Wrapper API does not record the owners of Kind clusters
+var KindOwnershipNotSupportedError = errors.New("kind api does not support ownership")
+
 // KindClusterStatus defines a structure of Kind cluster status retrieved from the Kind Wrapper API
+// Reason and Message are included for failed clusters
 type KindClusterStatus struct {
-	State KindState `json:"state"`
-	Host  string    `json:"host,omitempty"`
-	Port  int       `json:"port,omitempty"`
+	State   KindState `json:"state"`
+	Host    string    `json:"host,omitempty"`
+	Port    int       `json:"port,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	Message string    `json:"message,omitempty"`
 }
 
-// KindClusterConfig defines the structure of a config YAML file for Kind clusters
-type KindClusterConfig struct {
-	v1alpha1.KindClusterSpec `yaml:",inline"`
-	Kind                     string `yaml:"kind"`
-	APIVersion               string `yaml:"apiVersion"`
-	Name                     string `yaml:"name"`
+// KindNode defines a structure of a Kind cluster node retrieved from the Kind Wrapper API
+type KindNode struct {
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Image string `json:"image"`
+}
+
+// KindClusterDetails defines a structure of Kind cluster status, nodes and kubeconfig retrieved from the Kind Wrapper API
+// The kubeconfig is included only for running clusters
+type KindClusterDetails struct {
+	KindClusterStatus
+	Name       string     `json:"name"`
+	Nodes      []KindNode `json:"nodes"`
+	KubeConfig string     `json:"kubeconfig,omitempty"`
+}
+
+// KindHostResourceStats defines the total and free amount of a host resource retrieved from the Kind Wrapper API
+// CPU is measured in millicores, memory and disk in bytes
+type KindHostResourceStats struct {
+	Total int64 `json:"total"`
+	Free  int64 `json:"free"`
+}
+
+// KindHostStats defines a structure of host resources, versions and usage retrieved from the Kind Wrapper API
+// Clusters and Nodes include all Kind clusters on the host, even those not created by the provider
+type KindHostStats struct {
+	CPUs           int                   `json:"cpus"`
+	CPU            KindHostResourceStats `json:"cpu"`
+	Memory         KindHostResourceStats `json:"memory"`
+	Disk           KindHostResourceStats `json:"disk"`
+	Provider       string                `json:"provider"`
+	RuntimeVersion string                `json:"runtimeVersion"`
+	KindVersion    string                `json:"kindVersion"`
+	Clusters       int                   `json:"clusters"`
+	Nodes          int                   `json:"nodes"`
+}
+
+// KindClusterListItem defines a structure of a Kind cluster and its owner listed by the Kind Wrapper API
+// Owner is the UID of the KindCluster which created the Kind cluster, it is empty for Kind clusters not owned by a KindCluster
+// Manager identifies the management cluster of the owner, it is empty for owners recorded without it, e.g. by previous versions
+// State and CreatedAt are included only if the Kind clusters are filtered by them
+type KindClusterListItem struct {
+	Name      string            `json:"name"`
+	Owner     string            `json:"owner,omitempty"`
+	Manager   string            `json:"manager,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	State     KindState         `json:"state,omitempty"`
+	CreatedAt *time.Time        `json:"createdAt,omitempty"`
+	ExpiresAt *time.Time        `json:"expiresAt,omitempty"`
+}
+
+// KindClusterListOptions filter the Kind clusters listed by the Kind Wrapper API
+// LabelSelector matches the labels of the owners and OlderThan the creation time of the Kind clusters,
+// PageSize limits the number of Kind clusters listed by a single request
+// Kind Wrapper API versions without the clusterFilters feature ignore the options and list all Kind clusters
+type KindClusterListOptions struct {
+	LabelSelector labels.Selector
+	State         KindState
+	OlderThan     time.Duration
+	PageSize      int
+}
+
+// query returns the query parameters of the options, the continue token of the requested page is included if set
+func (o KindClusterListOptions) query(continueToken string) neturl.Values {
+	query := neturl.Values{}
+	if o.LabelSelector != nil && !o.LabelSelector.Empty() {
+		query.Set(kindApiParamLabelSelector, o.LabelSelector.String())
+	}
+	if o.State != "" {
+		query.Set(kindApiParamState, string(o.State))
+	}
+	if o.OlderThan > 0 {
+		query.Set(kindApiParamOlderThan, o.OlderThan.String())
+	}
+	if o.PageSize > 0 {
+		query.Set(kindApiParamLimit, strconv.Itoa(o.PageSize))
+	}
+	if continueToken != "" {
+		query.Set(kindApiParamContinue, continueToken)
+	}
+	return query
+}
+
+// KindClusterOwner defines the owner of a Kind cluster recorded by the Kind Wrapper API
+// Manager identifies the management cluster of the KindCluster, so that management clusters sharing a Kind Wrapper API tell their Kind clusters apart
+// The labels are recorded with the owner, so that the Kind clusters can be listed by them, they are not applied to the Kind cluster
+// TTL is the time the Kind Wrapper API keeps the Kind cluster after its creation, it is sent on creation only
+type KindClusterOwner struct {
+	UID     string
+	Manager string
+	Labels  map[string]string
+	TTL     time.Duration
+}
+
+// query returns the query parameters of the owner, the manager and the labels are sent only if set
+func (o KindClusterOwner) query() neturl.Values {
+	query := neturl.Values{kindApiParamOwner: []string{o.UID}}
+	if o.Manager != "" {
+		query.Set(kindApiParamManager, o.Manager)
+	}
+	keys := make([]string, 0, len(o.Labels))
+	for key := range o.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		query.Add(kindApiParamLabel, key+"="+o.Labels[key])
+	}
+	return query
+}
+
+// KindCapabilities defines a structure of the Kind Providers and features supported by the Kind Wrapper API
+type KindCapabilities struct {
+	APIVersion  string   `json:"apiVersion"`
+	KindVersion string   `json:"kindVersion"`
+	Providers   []string `json:"providers"`
+	Features    []string `json:"features"`
 }
 
 // KindClient communicates with Kind Wrapper API external service via HTTP
+// Every call is limited by a timeout, idempotent calls are retried with a jittered backoff
+// and the calls are stopped by a circuit breaker while the Kind Wrapper API keeps failing
 type KindClient struct {
-	host   string
-	client *http.Client
+	host          string
+	client        *http.Client
+	token         string
+	timeout       time.Duration
+	createTimeout time.Duration
+	backoff       wait.Backoff
+	breaker       *circuitBreaker
 }
 
 // NewKindClient creates a new instance of KindClient with host read from an environment variable if it exists
+// A bearer token is read from an environment variable as well, it is not sent if empty
 func NewKindClient() *KindClient {
 	host := os.Getenv(kindAPIHostEnvName)
 	if host == "" {
 		host = kindAPIDefaultHost
 	}
-	return &KindClient{host: host, client: &http.Client{}}
+	return newKindClient(host, &http.Client{}, os.Getenv(kindAPITokenEnvName))
+}
+
+// NewKindClientForHost creates a new instance of KindClient for a Kind Wrapper API with the specified URL
+// The token is sent as a bearer token if not empty, the CA bundle is used to verify the server certificate if not empty
+func NewKindClientForHost(url, token string, caBundle []byte) (*KindClient, error) {
+	httpClient := &http.Client{}
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, errors.New("failed to parse CA bundle")
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		httpClient.Transport = transport
+	}
+	return newKindClient(strings.TrimSuffix(url, "/"), httpClient, token), nil
+}
+
+// newKindClient creates a new instance of KindClient with the default timeouts, retries and circuit breaker
+func newKindClient(host string, httpClient *http.Client, token string) *KindClient {
+	return &KindClient{
+		host:          host,
+		client:        httpClient,
+		token:         token,
+		timeout:       kindApiRequestTimeout,
+		createTimeout: kindApiCreateTimeout,
+		backoff:       wait.Backoff{Duration: kindApiRetryInterval, Factor: 2, Jitter: 0.5, Steps: kindApiRetryAttempts},
+		breaker:       newCircuitBreaker(host),
+	}
 }
 
 // CreateCluster sends a POST request with a Kind cluster configuration YAML to create a new Kind cluster
-// Namespaced name must be unused, otherwise an error is returned
-// A response is received when the Kind cluster gets created, not when it gets ready
-func (u *KindClient) CreateCluster(namespace, name string, spec v1alpha1.KindClusterSpec) error {
-	clusterName := compositeClusterName(namespace, name)
+// The configuration is converted from the spec to the kind.x-k8s.io/v1alpha4 format
+// unless the spec contains a raw config, which is validated and sent as-is with the cluster name injected
+// The Kind cluster name must be unused, otherwise an error is returned
+// KindClusterInvalidConfigError is returned when the Kind Wrapper API rejects the configuration
+// The owner, the UID of the KindCluster and its labels, is recorded by the Kind Wrapper API, so that orphaned Kind clusters can be found
+// The Kind Wrapper API deletes the Kind cluster by itself once the TTL of the owner passes, if set
+// A response is received when the Kind cluster gets created, not when it gets ready, so the request is not retried
+func (u *KindClient) CreateCluster(ctx context.Context, clusterName string, owner KindClusterOwner, spec v1alpha1.KindClusterSpec) error {
 	url := fmt.Sprintf("%s%s", u.host, kindApiPathCluster)
-	payload := KindClusterConfig{
-		KindClusterSpec: spec,
-		Kind:            kindClusterKind,
-		APIVersion:      kindClusterAPIVersion,
-		Name:            clusterName,
+	if owner.UID != "" || len(owner.Labels) > 0 || owner.TTL > 0 {
+		query := owner.query()
+		if owner.TTL > 0 {
+			query.Set(kindApiParamTTL, owner.TTL.String())
+		}
+		url = fmt.Sprintf("%s?%s", url, query.Encode())
+	}
+	var yamlBytes []byte
+	var err error
+	if spec.RawConfig != "" {
+		yamlBytes, err = rawKindConfigWithName(spec.RawConfig, clusterName)
+	} else {
+		yamlBytes, err = yaml.Marshal(spec.KindConfig(clusterName))
 	}
-
-	yamlBytes, err := yaml.Marshal(payload)
 	if err != nil {
 		return err
 	}
 
-	response, err := u.client.Post(url, "text/plain; charset=utf8", bytes.NewBuffer(yamlBytes))
+	response, err := u.do(ctx, kindApiEndpointCreateCluster, http.MethodPost, url, yamlBytes)
 	if err != nil {
 		return err
 	}
 
+	if response.StatusCode == http.StatusBadRequest {
+		return fmt.Errorf("%w: %s", KindClusterInvalidConfigError, response.errorDetails())
+	}
+
 	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("received error status %d from kind api", response.StatusCode)
+		return fmt.Errorf("received error status %d from kind api: %s", response.StatusCode, response.errorDetails())
 	}
 
 	return nil
@@ -93,23 +319,93 @@ func (u *KindClient) CreateCluster(namespace, name string, spec v1alpha1.KindClu
 
 // DeleteCluster sends a DELETE request to delete a Kind cluster with a specified name
 // A response is received when the Kind Wrapper API starts the deletion process, not when delete is finished
-func (u *KindClient) DeleteCluster(namespace, name string) error {
-	clusterName := compositeClusterName(namespace, name)
+func (u *KindClient) DeleteCluster(ctx context.Context, clusterName string) error {
 	url := fmt.Sprintf("%s%s/%s", u.host, kindApiPathCluster, clusterName)
-	request, err := http.NewRequest(http.MethodDelete, url, nil)
+	response, err := u.do(ctx, kindApiEndpointDeleteCluster, http.MethodDelete, url, nil)
 	if err != nil {
 		return err
 	}
 
-	response, err := u.client.Do(request)
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("received error status %d from kind api", response.StatusCode)
+	}
+
+	return nil
+}
+
+// ListClusters sends GET requests to list the Kind clusters matching the options with their owners and labels,
+// including clusters not created by the provider, the pages of the Kind clusters are requested until the last one
+// KindOwnershipNotSupportedError is returned by Kind Wrapper API versions without the list endpoint
+func (u *KindClient) ListClusters(ctx context.Context, options KindClusterListOptions) ([]KindClusterListItem, error) {
+	var clusters []KindClusterListItem
+	continueToken := ""
+	for {
+		url := fmt.Sprintf("%s%s", u.host, kindApiPathClusters)
+		if query := options.query(continueToken); len(query) > 0 {
+			url = fmt.Sprintf("%s?%s", url, query.Encode())
+		}
+
+		response, err := u.do(ctx, kindApiEndpointListClusters, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode == http.StatusNotFound {
+			return nil, KindOwnershipNotSupportedError
+		}
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("received error status %d from kind api: %s", response.StatusCode, response.errorDetails())
+		}
+
+		var page []KindClusterListItem
+		if err := json.Unmarshal(response.Body, &page); err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, page...)
+		if continueToken = response.Header.Get(kindApiHeaderContinue); continueToken == "" {
+			return clusters, nil
+		}
+	}
+}
+
+// SetClusterOwner sends a PUT request to record the owner of an existing Kind cluster, an empty owner releases the Kind cluster
+// The recorded labels are kept unless the owner has labels, the labels of the nodes are not changed
+// KindClusterNotFoundError is returned when the cluster does not exist or the Kind Wrapper API does not record owners
+func (u *KindClient) SetClusterOwner(ctx context.Context, clusterName string, owner KindClusterOwner) error {
+	url := fmt.Sprintf("%s%s/%s%s?%s", u.host, kindApiPathCluster, clusterName, kindApiPathOwner, owner.query().Encode())
+	response, err := u.do(ctx, kindApiEndpointClusterOwner, http.MethodPut, url, nil)
 	if err != nil {
 		return err
 	}
 
+	if response.StatusCode == http.StatusNotFound {
+		return KindClusterNotFoundError
+	}
 	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("received error status %d from kind api", response.StatusCode)
+		return fmt.Errorf("received error status %d from kind api: %s", response.StatusCode, response.errorDetails())
+	}
+	return nil
+}
+
+// SetClusterTTL sends a PUT request to set the time the Kind Wrapper API keeps an existing Kind cluster from now on, a zero TTL keeps it until deleted
+// KindClusterNotFoundError is returned when the cluster does not exist or the Kind Wrapper API does not support TTLs
+func (u *KindClient) SetClusterTTL(ctx context.Context, clusterName string, ttl time.Duration) error {
+	query := neturl.Values{}
+	if ttl > 0 {
+		query.Set(kindApiParamTTL, ttl.String())
+	}
+	url := fmt.Sprintf("%s%s/%s%s?%s", u.host, kindApiPathCluster, clusterName, kindApiPathTTL, query.Encode())
+	response, err := u.do(ctx, kindApiEndpointClusterTTL, http.MethodPut, url, nil)
+	if err != nil {
+		return err
 	}
 
+	if response.StatusCode == http.StatusNotFound {
+		return KindClusterNotFoundError
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("received error status %d from kind api: %s", response.StatusCode, response.errorDetails())
+	}
 	return nil
 }
 
@@ -119,13 +415,11 @@ func (u *KindClient) DeleteCluster(namespace, name string) error {
 // Control plane host and port are included in the response for running clusters
 // KindClusterNotFoundError is returhen when the cluster does not exist
 // any other error means that the clent was unable to retrieve the cluster status
-func (u *KindClient) GetClusterStatus(namespace, name string) (KindClusterStatus, error) {
-	clusterName := compositeClusterName(namespace, name)
+func (u *KindClient) GetClusterStatus(ctx context.Context, clusterName string) (KindClusterStatus, error) {
 	url := fmt.Sprintf("%s%s/%s", u.host, kindApiPathCluster, clusterName)
-	response, err := u.client.Get(url)
-
 	var clusterStatus KindClusterStatus
 
+	response, err := u.do(ctx, kindApiEndpointClusterStatus, http.MethodGet, url, nil)
 	if err != nil {
 		return clusterStatus, err
 	}
@@ -134,13 +428,240 @@ func (u *KindClient) GetClusterStatus(namespace, name string) (KindClusterStatus
 		return clusterStatus, KindClusterNotFoundError
 	}
 
-	err = json.NewDecoder(response.Body).Decode(&clusterStatus)
+	err = json.Unmarshal(response.Body, &clusterStatus)
 	if err != nil {
 		return KindClusterStatus{}, err
 	}
 	return clusterStatus, nil
 }
 
+// GetClusterDetails sends a GET request to look up a Kind cluster by its name, including clusters not created by the provider
+// KindClusterNotFoundError is returned when the cluster does not exist
+func (u *KindClient) GetClusterDetails(ctx context.Context, clusterName string) (KindClusterDetails, error) {
+	url := fmt.Sprintf("%s%s/%s%s", u.host, kindApiPathCluster, clusterName, kindApiPathDetails)
+	var clusterDetails KindClusterDetails
+
+	response, err := u.do(ctx, kindApiEndpointClusterDetails, http.MethodGet, url, nil)
+	if err != nil {
+		return clusterDetails, err
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return clusterDetails, KindClusterNotFoundError
+	}
+	if response.StatusCode != http.StatusOK {
+		return clusterDetails, fmt.Errorf("received error status %d from kind api: %s", response.StatusCode, response.errorDetails())
+	}
+
+	err = json.Unmarshal(response.Body, &clusterDetails)
+	if err != nil {
+		return KindClusterDetails{}, err
+	}
+	return clusterDetails, nil
+}
+
+// Ping sends a GET request to the liveness endpoint to check that the Kind Wrapper API is reachable
+func (u *KindClient) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s%s", u.host, kindApiPathHealth)
+	response, err := u.do(ctx, kindApiEndpointHealth, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("received error status %d from kind api", response.StatusCode)
+	}
+	return nil
+}
+
+// GetHostStats sends a GET request to get the resources of the host the Kind Wrapper API runs on
+func (u *KindClient) GetHostStats(ctx context.Context) (KindHostStats, error) {
+	url := fmt.Sprintf("%s%s", u.host, kindApiPathHost)
+	var hostStats KindHostStats
+
+	response, err := u.do(ctx, kindApiEndpointHost, http.MethodGet, url, nil)
+	if err != nil {
+		return hostStats, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return hostStats, fmt.Errorf("received error status %d from kind api: %s", response.StatusCode, response.errorDetails())
+	}
+
+	err = json.Unmarshal(response.Body, &hostStats)
+	if err != nil {
+		return KindHostStats{}, err
+	}
+	return hostStats, nil
+}
+
+// GetCapabilities sends a GET request to get the Kind Providers and features supported by the Kind Wrapper API
+// KindCapabilitiesNotSupportedError is returned by Kind Wrapper API versions without the capabilities endpoint
+func (u *KindClient) GetCapabilities(ctx context.Context) (KindCapabilities, error) {
+	url := fmt.Sprintf("%s%s", u.host, kindApiPathCapabilities)
+	var capabilities KindCapabilities
+
+	response, err := u.do(ctx, kindApiEndpointCapabilities, http.MethodGet, url, nil)
+	if err != nil {
+		return capabilities, err
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return capabilities, KindCapabilitiesNotSupportedError
+	}
+	if response.StatusCode != http.StatusOK {
+		return capabilities, fmt.Errorf("received error status %d from kind api: %s", response.StatusCode, response.errorDetails())
+	}
+
+	err = json.Unmarshal(response.Body, &capabilities)
+	if err != nil {
+		return KindCapabilities{}, err
+	}
+	return capabilities, nil
+}
+
+// rawKindConfigWithName validates a raw Kind config and sets its name, the rest of the document is kept unchanged
+func rawKindConfigWithName(rawConfig, name string) ([]byte, error) {
+	if _, err := v1alpha1.ParseKindConfig(rawConfig); err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(rawConfig), &document); err != nil {
+		return nil, err
+	}
+	config := document.Content[0]
+	for i := 0; i < len(config.Content); i += 2 {
+		if config.Content[i].Value == "name" {
+			config.Content[i+1].SetString(name)
+			return yaml.Marshal(&document)
+		}
+	}
+	nameKey, nameValue := &yaml.Node{}, &yaml.Node{}
+	nameKey.SetString("name")
+	nameValue.SetString(name)
+	config.Content = append(config.Content, nameKey, nameValue)
+	return yaml.Marshal(&document)
+}
+
+// kindAPIResponse is a response of the Kind Wrapper API with the body read completely
+type kindAPIResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// errorDetails returns the error details from the response body
+func (r *kindAPIResponse) errorDetails() string {
+	return strings.TrimSpace(string(r.Body))
+}
+
+// do sends a request to the Kind Wrapper API and reads its response within the timeout of the call
+// GET, PUT and DELETE requests are idempotent and they are retried with a jittered backoff on transport and server errors
+// KindAPICircuitOpenError is returned without sending the request while the circuit breaker is open
+// Every attempt is counted by the request metric of the endpoint
+func (u *KindClient) do(ctx context.Context, endpoint, method, url string, body []byte) (*kindAPIResponse, error) {
+	idempotent := method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+	backoff := u.backoff
+	for {
+		response, err := u.send(ctx, method, url, body)
+		kindAPIRequests.WithLabelValues(u.host, endpoint, kindAPIRequestResult(response, err)).Inc()
+		if !idempotent || !isRetriable(response, err) || errors.Is(err, KindAPICircuitOpenError) || backoff.Steps <= 1 {
+			return response, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+// send sends a single request to the Kind Wrapper API and records its outcome in the circuit breaker
+func (u *KindClient) send(ctx context.Context, method, url string, body []byte) (*kindAPIResponse, error) {
+	if err := u.breaker.allow(); err != nil {
+		return nil, err
+	}
+	timeout := u.timeout
+	if method == http.MethodPost {
+		timeout = u.createTimeout
+	}
+	requestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	response, err := u.sendWithContext(requestCtx, method, url, body)
+	// Requests cancelled by the caller say nothing about the Kind Wrapper API
+	if ctx.Err() == nil {
+		u.breaker.record(!isRetriable(response, err))
+	}
+	return response, err
+}
+
+// sendWithContext sends a request authorized by the bearer token if configured and reads the whole response body
+func (u *KindClient) sendWithContext(ctx context.Context, method, url string, body []byte) (*kindAPIResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "text/plain; charset=utf8")
+	}
+	if u.token != "" {
+		request.Header.Set("Authorization", "Bearer "+u.token)
+	}
+
+	response, err := u.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &kindAPIResponse{StatusCode: response.StatusCode, Header: response.Header, Body: responseBody}, nil
+}
+
+// isRetriable checks if the request failed due to the transport or the Kind Wrapper API
+func isRetriable(response *kindAPIResponse, err error) bool {
+	return err != nil || response.StatusCode >= http.StatusInternalServerError
+}
+
+// compositeClusterName returns the name of the Kind cluster of a KindCluster
+// The namespace and name are joined by a dash only if the result is unambiguous, short enough and does not look like a hashed name,
+// otherwise the result is shortened and suffixed with a hash of the namespace and name
 func compositeClusterName(namespace, name string) string {
+	composite := legacyClusterName(namespace, name)
+	if !strings.Contains(namespace, "-") && len(composite) <= kindClusterNameMaxLength && !hasHashSuffix(composite) {
+		return composite
+	}
+	hash := sha256.Sum256([]byte(namespace + "/" + name))
+	suffix := hex.EncodeToString(hash[:])[:kindClusterNameHashLength]
+	prefix := composite
+	if maxLength := kindClusterNameMaxLength - kindClusterNameHashLength - 1; len(prefix) > maxLength {
+		prefix = strings.TrimRight(prefix[:maxLength], "-.")
+	}
+	return fmt.Sprintf("%s-%s", prefix, suffix)
+}
+
+// hasHashSuffix checks if a name ends with a dash and a hash like the names suffixed by compositeClusterName
+// Such names are reserved for the hashed form, so that a joined name never matches the hashed name of another KindCluster
+func hasHashSuffix(name string) bool {
+	if len(name) <= kindClusterNameHashLength {
+		return false
+	}
+	if name[len(name)-kindClusterNameHashLength-1] != '-' {
+		return false
+	}
+	_, err := hex.DecodeString(name[len(name)-kindClusterNameHashLength:])
+	return err == nil
+}
+
+// legacyClusterName returns the name of Kind clusters created before the name was recorded in the status
+func legacyClusterName(namespace, name string) string {
 	return fmt.Sprintf("%s-%s", namespace, name)
 }
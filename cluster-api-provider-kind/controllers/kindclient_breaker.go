@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sync"
+	"time"
+)
+
+// circuitBreakerState defines the states of a circuit breaker, the values are reported by the state metric
+type circuitBreakerState int
+
+const (
+	circuitBreakerClosed circuitBreakerState = iota
+	circuitBreakerOpen
+	circuitBreakerHalfOpen
+
+	kindApiCircuitBreakerThreshold = 5
+	kindApiCircuitBreakerCooldown  = 30 * time.Second
+)
+
+// KindAPICircuitOpenError is returned without calling the Kind Wrapper API while its circuit breaker is open
+var KindAPICircuitOpenError = errors.New("kind api circuit breaker is open")
+
+// kindAPICircuitBreakerState reports the circuit breaker state of every Kind Wrapper API, 0 closed, 1 open and 2 half-open
+var kindAPICircuitBreakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "capk_kind_api_circuit_breaker_state",
+	Help: "State of the circuit breaker of the Kind Wrapper API, 0 closed, 1 open and 2 half-open",
+}, []string{"host"})
+
+func init() {
+	metrics.Registry.MustRegister(kindAPICircuitBreakerState)
+}
+
+// circuitBreaker stops the calls of a Kind Wrapper API after the threshold of consecutive failures for the cooldown period
+// A single trial call is let through once the cooldown elapses, it closes the breaker on success and opens it again on failure
+type circuitBreaker struct {
+	host      string
+	threshold int
+	cooldown  time.Duration
+	mutex     sync.Mutex
+	state     circuitBreakerState
+	failures  int
+	changedAt time.Time
+}
+
+// newCircuitBreaker creates a new closed circuit breaker of the Kind Wrapper API with the specified URL
+func newCircuitBreaker(host string) *circuitBreaker {
+	breaker := &circuitBreaker{host: host, threshold: kindApiCircuitBreakerThreshold, cooldown: kindApiCircuitBreakerCooldown}
+	breaker.setState(circuitBreakerClosed)
+	return breaker
+}
+
+// allow returns KindAPICircuitOpenError in case the call must not be sent
+// A half-open breaker lets another trial call through in case the outcome of the previous one was never recorded
+func (b *circuitBreaker) allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state == circuitBreakerClosed {
+		return nil
+	}
+	if time.Since(b.changedAt) < b.cooldown {
+		return KindAPICircuitOpenError
+	}
+	b.setState(circuitBreakerHalfOpen)
+	return nil
+}
+
+// record records the outcome of a call
+func (b *circuitBreaker) record(success bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if success {
+		b.failures = 0
+		if b.state != circuitBreakerClosed {
+			b.setState(circuitBreakerClosed)
+		}
+		return
+	}
+	b.failures++
+	if b.state == circuitBreakerHalfOpen || b.failures >= b.threshold {
+		b.setState(circuitBreakerOpen)
+	}
+}
+
+// setState changes the state and reports it by the metric, the mutex must be held
+func (b *circuitBreaker) setState(state circuitBreakerState) {
+	b.state = state
+	b.changedAt = time.Now()
+	kindAPICircuitBreakerState.WithLabelValues(b.host).Set(float64(state))
+}
@@ -2,23 +2,35 @@ package controllers
 
 import (
 	"cluster-api-provider-kind/api/v1alpha1"
+	"context"
+	"encoding/pem"
+	"errors"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
 )
 
 var _ = Describe("KindClient", func() {
 
 	var namespace string
 	var name string
+	var clusterName string
 	var kindClient *KindClient
 	var spec v1alpha1.KindClusterSpec
 
 	BeforeEach(func() {
 		namespace = "default"
 		name = "kind-cluster"
+		clusterName = compositeClusterName(namespace, name)
 		spec = v1alpha1.KindClusterSpec{Nodes: []v1alpha1.KindClusterNode{{Role: "control-plane"}}}
-		kindClient = &KindClient{host: mockKindApiServer.server.URL, client: http.DefaultClient}
+		kindClient = newKindClient(mockKindApiServer.server.URL, http.DefaultClient, "")
 	})
 
 	AfterEach(func() {
@@ -27,42 +39,473 @@ var _ = Describe("KindClient", func() {
 
 	It("should handle cluster creation", func() {
 		mockKindApiServer.SetDefaultCreateResponse(SimpleSuccessMockApiResponse)
-		Expect(kindClient.CreateCluster(namespace, name, spec)).To(Succeed())
+		Expect(kindClient.CreateCluster(context.Background(), clusterName, KindClusterOwner{}, spec)).To(Succeed())
 
 		mockKindApiServer.SetDefaultCreateResponse(InternalServerErrorResponse)
-		Expect(kindClient.CreateCluster(namespace, name, spec)).NotTo(Succeed())
+		err := kindClient.CreateCluster(context.Background(), clusterName, KindClusterOwner{}, spec)
+		Expect(err).To(MatchError(ContainSubstring("Internal Server Error")))
+		Expect(errors.Is(err, KindClusterInvalidConfigError)).To(BeFalse())
+
+		mockKindApiServer.SetDefaultCreateResponse(BadRequestMockApiResponse)
+		err = kindClient.CreateCluster(context.Background(), clusterName, KindClusterOwner{}, spec)
+		Expect(errors.Is(err, KindClusterInvalidConfigError)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("Failed to parse request payload")))
+	})
+
+	It("should send structured spec as a Kind config", func() {
+		spec.Networking.DNSSearch = &[]string{"example.com"}
+		spec.ControlPlaneEndpoint = v1alpha1.KindClusterControlPlaneEndpoint{Host: "127.0.0.1", Port: 6443}
+		Expect(kindClient.CreateCluster(context.Background(), clusterName, KindClusterOwner{}, spec)).To(Succeed())
+		Expect(mockKindApiServer.CreatePayloads()).To(HaveLen(1))
+
+		config, err := v1alpha1.ParseKindConfig(mockKindApiServer.CreatePayloads()[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Name).To(Equal("default-kind-cluster"))
+		Expect(config.Networking.DNSSearch).To(Equal(&[]string{"example.com"}))
+	})
+
+	It("should send raw config with the cluster name injected", func() {
+		rawSpec := v1alpha1.KindClusterSpec{RawConfig: "# tuned config\nkind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nname: ignored\nnodes:\n- role: control-plane\n"}
+		Expect(kindClient.CreateCluster(context.Background(), clusterName, KindClusterOwner{}, rawSpec)).To(Succeed())
+		rawSpec.RawConfig = "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\n"
+		Expect(kindClient.CreateCluster(context.Background(), clusterName, KindClusterOwner{}, rawSpec)).To(Succeed())
+		Expect(mockKindApiServer.CreatePayloads()).To(HaveLen(2))
+
+		Expect(mockKindApiServer.CreatePayloads()[0]).To(ContainSubstring("# tuned config"))
+		for _, payload := range mockKindApiServer.CreatePayloads() {
+			config, err := v1alpha1.ParseKindConfig(payload)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Name).To(Equal("default-kind-cluster"))
+		}
+	})
+
+	It("should reject invalid raw config without calling the Kind API", func() {
+		rawSpec := v1alpha1.KindClusterSpec{RawConfig: "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nunknown: field\n"}
+		err := kindClient.CreateCluster(context.Background(), clusterName, KindClusterOwner{}, rawSpec)
+		Expect(errors.Is(err, v1alpha1.InvalidKindConfigError)).To(BeTrue())
+		Expect(mockKindApiServer.CreatePayloads()).To(BeEmpty())
 	})
 
 	It("should handle cluster deletion", func() {
 		mockKindApiServer.SetDefaultDeleteResponse(SimpleSuccessMockApiResponse)
-		Expect(kindClient.DeleteCluster(namespace, name)).To(Succeed())
+		Expect(kindClient.DeleteCluster(context.Background(), clusterName)).To(Succeed())
 
 		mockKindApiServer.SetDefaultDeleteResponse(InternalServerErrorResponse)
-		Expect(kindClient.DeleteCluster(namespace, name)).NotTo(Succeed())
+		Expect(kindClient.DeleteCluster(context.Background(), clusterName)).NotTo(Succeed())
 	})
 
 	It("should retrieve cluster status", func() {
 		mockKindApiServer.SetDefaultStatusResponse(NotFoundMockApiResponse)
-		_, err := kindClient.GetClusterStatus(namespace, name)
+		_, err := kindClient.GetClusterStatus(context.Background(), clusterName)
 		Expect(err).To(HaveOccurred())
 		Expect(err).To(Equal(KindClusterNotFoundError))
 
 		mockKindApiServer.SetDefaultStatusResponse(PendingStatusMockApiResponse)
-		status, err := kindClient.GetClusterStatus(namespace, name)
+		status, err := kindClient.GetClusterStatus(context.Background(), clusterName)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(status.State).To(Equal(KindStatePending))
 
 		mockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
-		status, err = kindClient.GetClusterStatus(namespace, name)
+		status, err = kindClient.GetClusterStatus(context.Background(), clusterName)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(status.State).To(Equal(KindStateRunning))
 		Expect(status.Host).To(Equal("127.0.0.1"))
 		Expect(status.Port).To(Equal(6443))
 
+		mockKindApiServer.SetDefaultStatusResponse(FailedStatusMockApiResponse)
+		status, err = kindClient.GetClusterStatus(context.Background(), clusterName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.State).To(Equal(KindStateFailed))
+		Expect(status.Reason).To(Equal("CreateFailed"))
+		Expect(status.Message).To(Equal("node image not found"))
+
 		mockKindApiServer.SetDefaultStatusResponse(InternalServerErrorResponse)
-		_, err = kindClient.GetClusterStatus(namespace, name)
+		_, err = kindClient.GetClusterStatus(context.Background(), clusterName)
 		Expect(err).To(HaveOccurred())
 		Expect(err).NotTo(Equal(KindClusterNotFoundError))
 	})
 
+	It("should ping the Kind Wrapper API", func() {
+		Expect(kindClient.Ping(context.Background())).To(Succeed())
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		hostClient, err := NewKindClientForHost(server.URL, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hostClient.Ping(context.Background())).To(MatchError(ContainSubstring("404")))
+	})
+
+	It("should retrieve host stats", func() {
+		stats, err := kindClient.GetHostStats(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.CPU).To(Equal(KindHostResourceStats{Total: 8000, Free: 6000}))
+		Expect(stats.Memory.Free).To(Equal(int64(8589934592)))
+		Expect(stats.Disk.Total).To(Equal(int64(107374182400)))
+		Expect(stats.CPUs).To(Equal(8))
+		Expect(stats.KindVersion).To(Equal("0.18.0"))
+		Expect(stats.Clusters).To(Equal(2))
+
+		mockKindApiServer.SetHostResponse(InternalServerErrorResponse)
+		defer mockKindApiServer.SetHostResponse(HostStatsMockApiResponse)
+		_, err = kindClient.GetHostStats(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("Internal Server Error")))
+	})
+
+	It("should retrieve capabilities", func() {
+		capabilities, err := kindClient.GetCapabilities(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(capabilities.APIVersion).To(Equal("v1"))
+		Expect(capabilities.Providers).To(Equal([]string{"docker"}))
+		Expect(capabilities.Features).To(ContainElement("hostInfo"))
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		hostClient, err := NewKindClientForHost(server.URL, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = hostClient.GetCapabilities(context.Background())
+		Expect(err).To(Equal(KindCapabilitiesNotSupportedError))
+	})
+
+	It("should record owners of Kind clusters", func() {
+		var requests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.RequestURI())
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`[{"name":"owned","owner":"1234","manager":"mgmt","labels":{"team":"payments"}},{"name":"hand-made"}]`))
+			}
+		}))
+		defer server.Close()
+		hostClient, err := NewKindClientForHost(server.URL, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		owner := KindClusterOwner{UID: "1234", Manager: "mgmt", Labels: map[string]string{"team": "payments", "tier": "preview"}}
+		Expect(hostClient.CreateCluster(context.Background(), clusterName, owner, spec)).To(Succeed())
+		Expect(hostClient.SetClusterOwner(context.Background(), clusterName, KindClusterOwner{})).To(Succeed())
+		clusters, err := hostClient.ListClusters(context.Background(), KindClusterListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusters).To(Equal([]KindClusterListItem{{Name: "owned", Owner: "1234", Manager: "mgmt", Labels: map[string]string{"team": "payments"}}, {Name: "hand-made"}}))
+		Expect(requests).To(Equal([]string{
+			"POST /api/v1/cluster?label=team%3Dpayments&label=tier%3Dpreview&manager=mgmt&owner=1234",
+			"PUT /api/v1/cluster/" + clusterName + "/owner?owner=",
+			"GET /api/v1/clusters",
+		}))
+
+		Expect(kindClient.SetClusterOwner(context.Background(), clusterName, KindClusterOwner{UID: "1234"})).To(Equal(KindClusterNotFoundError))
+		_, err = kindClient.ListClusters(context.Background(), KindClusterListOptions{})
+		Expect(err).To(Equal(KindOwnershipNotSupportedError))
+	})
+
+	It("should send the TTL of Kind clusters", func() {
+		var requests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		}))
+		defer server.Close()
+		hostClient, err := NewKindClientForHost(server.URL, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(hostClient.CreateCluster(context.Background(), clusterName, KindClusterOwner{TTL: 2 * time.Hour}, spec)).To(Succeed())
+		Expect(hostClient.SetClusterTTL(context.Background(), clusterName, 30*time.Minute)).To(Succeed())
+		Expect(hostClient.SetClusterTTL(context.Background(), clusterName, 0)).To(Succeed())
+		Expect(requests).To(Equal([]string{
+			"POST /api/v1/cluster?owner=&ttl=2h0m0s",
+			"PUT /api/v1/cluster/" + clusterName + "/ttl?ttl=30m0s",
+			"PUT /api/v1/cluster/" + clusterName + "/ttl?",
+		}))
+
+		Expect(kindClient.SetClusterTTL(context.Background(), clusterName, time.Hour)).To(Equal(KindClusterNotFoundError))
+	})
+
+	It("should list filtered Kind clusters page by page", func() {
+		var requests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.RequestURI())
+			if r.URL.Query().Get(kindApiParamContinue) == "" {
+				w.Header().Set(kindApiHeaderContinue, "b25l")
+				_, _ = w.Write([]byte(`[{"name":"one","owner":"1","state":"running","createdAt":"2023-05-01T10:00:00Z"}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"name":"two","owner":"2","state":"running","createdAt":"2023-05-01T11:00:00Z"}]`))
+		}))
+		defer server.Close()
+		hostClient, err := NewKindClientForHost(server.URL, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		selector, err := labels.Parse("team=payments")
+		Expect(err).NotTo(HaveOccurred())
+		clusters, err := hostClient.ListClusters(context.Background(), KindClusterListOptions{
+			LabelSelector: selector, State: KindStateRunning, OlderThan: 24 * time.Hour, PageSize: 1,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusters).To(HaveLen(2))
+		Expect(clusters[1].Name).To(Equal("two"))
+		Expect(clusters[1].State).To(Equal(KindStateRunning))
+		Expect(*clusters[1].CreatedAt).To(BeTemporally("==", time.Date(2023, 5, 1, 11, 0, 0, 0, time.UTC)))
+		Expect(requests).To(Equal([]string{
+			"/api/v1/clusters?labelSelector=team%3Dpayments&limit=1&olderThan=24h0m0s&state=running",
+			"/api/v1/clusters?continue=b25l&labelSelector=team%3Dpayments&limit=1&olderThan=24h0m0s&state=running",
+		}))
+	})
+
+	It("should identify the KindCluster by the labels of its owner", func() {
+		kindCluster := &v1alpha1.KindCluster{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace, Name: name, UID: "1234", Labels: map[string]string{"team": "payments"},
+		}}
+		Expect(kindClusterOwner(kindCluster, "mgmt")).To(Equal(KindClusterOwner{UID: "1234", Manager: "mgmt", Labels: map[string]string{
+			"team":                             "payments",
+			v1alpha1.KindClusterNamespaceLabel: namespace,
+			v1alpha1.KindClusterNameLabel:      name,
+			v1alpha1.KindClusterUIDLabel:       "1234",
+		}}))
+
+		kindCluster.Name = strings.Repeat("a", 64)
+		Expect(kindClusterOwner(kindCluster, "mgmt").Labels).NotTo(HaveKey(v1alpha1.KindClusterNameLabel))
+	})
+
+	It("should release a kept Kind cluster and clear its TTL", func() {
+		var requests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		}))
+		defer server.Close()
+		hostClient, err := NewKindClientForHost(server.URL, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		kindCluster := &v1alpha1.KindCluster{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		kindCluster.Status.KindClusterName = clusterName
+		Expect(releaseKindCluster(context.Background(), hostClient, kindCluster)).To(Succeed())
+		kindCluster.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+		Expect(releaseKindCluster(context.Background(), hostClient, kindCluster)).To(Succeed())
+		Expect(requests).To(Equal([]string{
+			"PUT /api/v1/cluster/" + clusterName + "/owner?owner=",
+			"PUT /api/v1/cluster/" + clusterName + "/owner?owner=",
+			"PUT /api/v1/cluster/" + clusterName + "/ttl?",
+		}))
+	})
+
+	It("should look up a Kind cluster by its name", func() {
+		_, err := kindClient.GetClusterDetails(context.Background(), "hand-made")
+		Expect(err).To(Equal(KindClusterNotFoundError))
+
+		mockKindApiServer.SetDetailsResponse(DetailsMockApiResponse)
+		details, err := kindClient.GetClusterDetails(context.Background(), "hand-made")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(details.Name).To(Equal("hand-made"))
+		Expect(details.State).To(Equal(KindStateRunning))
+		Expect(details.Port).To(Equal(6443))
+		Expect(details.Nodes).To(Equal([]KindNode{
+			{Name: "hand-made-control-plane", Role: "control-plane", Image: "kindest/node:v1.26.3"},
+			{Name: "hand-made-worker", Role: "worker", Image: "kindest/node:v1.26.3"},
+		}))
+		Expect(details.KubeConfig).To(ContainSubstring("kind: Config"))
+
+		mockKindApiServer.SetDetailsResponse(InternalServerErrorResponse)
+		_, err = kindClient.GetClusterDetails(context.Background(), "hand-made")
+		Expect(err).To(MatchError(ContainSubstring("Internal Server Error")))
+	})
+
+	It("should send the bearer token of the host", func() {
+		var authorizations []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"state":"pending"}`))
+		}))
+		defer server.Close()
+
+		hostClient, err := NewKindClientForHost(server.URL+"/", "secret", nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = hostClient.GetClusterStatus(context.Background(), clusterName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hostClient.CreateCluster(context.Background(), clusterName, KindClusterOwner{}, spec)).To(Succeed())
+		Expect(hostClient.DeleteCluster(context.Background(), clusterName)).To(Succeed())
+		Expect(authorizations).To(Equal([]string{"Bearer secret", "Bearer secret", "Bearer secret"}))
+
+		hostClient, err = NewKindClientForHost(server.URL, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = hostClient.GetClusterStatus(context.Background(), clusterName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(authorizations[3]).To(BeEmpty())
+	})
+
+	It("should read the default host and bearer token from the environment", func() {
+		defer os.Unsetenv(kindAPIHostEnvName)
+		defer os.Unsetenv(kindAPITokenEnvName)
+		Expect(os.Setenv(kindAPIHostEnvName, "http://kind-api:8888")).To(Succeed())
+		Expect(os.Setenv(kindAPITokenEnvName, "secret")).To(Succeed())
+		defaultClient := NewKindClient()
+		Expect(defaultClient.host).To(Equal("http://kind-api:8888"))
+		Expect(defaultClient.token).To(Equal("secret"))
+
+		Expect(os.Unsetenv(kindAPIHostEnvName)).To(Succeed())
+		Expect(os.Unsetenv(kindAPITokenEnvName)).To(Succeed())
+		defaultClient = NewKindClient()
+		Expect(defaultClient.host).To(Equal(kindAPIDefaultHost))
+		Expect(defaultClient.token).To(BeEmpty())
+	})
+
+	It("should verify the host certificate with the CA bundle", func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"state":"running","host":"127.0.0.1","port":6443}`))
+		}))
+		defer server.Close()
+		caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+		hostClient, err := NewKindClientForHost(server.URL, "", caBundle)
+		Expect(err).NotTo(HaveOccurred())
+		status, err := hostClient.GetClusterStatus(context.Background(), clusterName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.State).To(Equal(KindStateRunning))
+
+		hostClient, err = NewKindClientForHost(server.URL, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = hostClient.GetClusterStatus(context.Background(), clusterName)
+		Expect(err).To(HaveOccurred())
+
+		_, err = NewKindClientForHost(server.URL, "", []byte("not a certificate"))
+		Expect(err).To(HaveOccurred())
+	})
+	It("should derive collision-free and length-safe Kind cluster names", func() {
+		Expect(compositeClusterName("default", "kind-cluster")).To(Equal("default-kind-cluster"))
+		Expect(compositeClusterName("a-b", "c")).NotTo(Equal(compositeClusterName("a", "b-c")))
+		Expect(compositeClusterName("a-b", "c")).To(Equal(compositeClusterName("a-b", "c")))
+
+		// A name ending like a hash is hashed as well, so that it cannot match the hashed name of another KindCluster
+		hashed := compositeClusterName("a-b", "c")
+		Expect(hashed).To(MatchRegexp("^a-b-c-[0-9a-f]{8}$"))
+		impostor := strings.TrimPrefix(hashed, "a-")
+		Expect(compositeClusterName("a", impostor)).NotTo(Equal(hashed))
+		Expect(compositeClusterName("a", impostor)).To(MatchRegexp("^a-" + impostor + "-[0-9a-f]{8}$"))
+
+		long := "a-very-long-kind-cluster-name-exceeding-the-limits-of-docker-hostnames"
+		shortened := compositeClusterName("default", long)
+		Expect(len(shortened)).To(BeNumerically("<=", kindClusterNameMaxLength))
+		Expect(shortened).To(HavePrefix("default-a-very-long"))
+		Expect(shortened).NotTo(Equal(compositeClusterName("default", long+"2")))
+		Expect(shortened).To(MatchRegexp("^[a-z0-9][a-z0-9.-]*[a-z0-9]$"))
+	})
+
+	It("should prefer the recorded Kind cluster name", func() {
+		kindCluster := &v1alpha1.KindCluster{}
+		kindCluster.Namespace = "my-namespace"
+		kindCluster.Name = "cluster"
+		Expect(kindClusterName(kindCluster)).To(Equal(compositeClusterName("my-namespace", "cluster")))
+
+		kindCluster.Status.AppliedSpecHash = "hash"
+		Expect(kindClusterName(kindCluster)).To(Equal("my-namespace-cluster"))
+
+		// KindClusters provisioned before the spec hash was recorded keep the legacy name as well
+		kindCluster.Status.AppliedSpecHash = ""
+		kindCluster.Status.State = v1alpha1.KindClusterStateRunning
+		Expect(kindClusterName(kindCluster)).To(Equal("my-namespace-cluster"))
+
+		kindCluster.Annotations = map[string]string{v1alpha1.KindClusterAdoptAnnotation: "demo"}
+		Expect(kindClusterName(kindCluster)).To(Equal("demo"))
+
+		kindCluster.Status.KindClusterName = "recorded"
+		Expect(kindClusterName(kindCluster)).To(Equal("recorded"))
+	})
+	It("should retry idempotent calls on server errors only", func() {
+		var methods []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method)
+			if len(methods) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"state":"running"}`))
+		}))
+		defer server.Close()
+
+		hostClient := newKindClient(server.URL, http.DefaultClient, "")
+		hostClient.backoff.Duration = time.Millisecond
+		status, err := hostClient.GetClusterStatus(context.Background(), clusterName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.State).To(Equal(KindStateRunning))
+		Expect(methods).To(Equal([]string{http.MethodGet, http.MethodGet, http.MethodGet}))
+
+		methods = nil
+		Expect(hostClient.CreateCluster(context.Background(), clusterName, KindClusterOwner{}, spec)).To(MatchError(ContainSubstring("503")))
+		Expect(methods).To(Equal([]string{http.MethodPost}))
+	})
+
+	It("should limit calls by the timeout and the context", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}))
+		defer server.Close()
+
+		hostClient := newKindClient(server.URL, http.DefaultClient, "")
+		hostClient.timeout = 50 * time.Millisecond
+		hostClient.backoff.Steps = 1
+		started := time.Now()
+		Expect(hostClient.Ping(context.Background())).To(MatchError(context.DeadlineExceeded))
+		Expect(time.Since(started)).To(BeNumerically("<", time.Second))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(hostClient.Ping(ctx)).To(MatchError(context.Canceled))
+	})
+
+	It("should count the requests of the Kind Wrapper API by endpoint and result", func() {
+		found := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(RunningStatusMockApiResponse.Payload))
+		}))
+		defer server.Close()
+
+		hostClient := newKindClient(server.URL, http.DefaultClient, "")
+		_, err := hostClient.GetClusterStatus(context.Background(), clusterName)
+		Expect(err).To(MatchError(KindClusterNotFoundError))
+		found = true
+		_, err = hostClient.GetClusterStatus(context.Background(), clusterName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(kindAPIRequests.WithLabelValues(server.URL, kindApiEndpointClusterStatus, "404"))).To(Equal(float64(1)))
+		Expect(testutil.ToFloat64(kindAPIRequests.WithLabelValues(server.URL, kindApiEndpointClusterStatus, "200"))).To(Equal(float64(1)))
+
+		server.Close()
+		hostClient.backoff.Steps = 1
+		Expect(hostClient.Ping(context.Background())).NotTo(Succeed())
+		Expect(testutil.ToFloat64(kindAPIRequests.WithLabelValues(server.URL, kindApiEndpointHealth, kindAPIResultError))).To(Equal(float64(1)))
+	})
+
+	It("should stop calls of a failing Kind Wrapper API by the circuit breaker", func() {
+		calls := 0
+		failing := true
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if failing {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		defer server.Close()
+
+		hostClient := newKindClient(server.URL, http.DefaultClient, "")
+		hostClient.backoff.Steps = 1
+		hostClient.breaker.cooldown = 100 * time.Millisecond
+		for i := 0; i < kindApiCircuitBreakerThreshold; i++ {
+			Expect(hostClient.Ping(context.Background())).To(MatchError(ContainSubstring("500")))
+		}
+		Expect(hostClient.Ping(context.Background())).To(MatchError(KindAPICircuitOpenError))
+		Expect(calls).To(Equal(kindApiCircuitBreakerThreshold))
+		Expect(testutil.ToFloat64(kindAPICircuitBreakerState.WithLabelValues(server.URL))).To(Equal(float64(circuitBreakerOpen)))
+
+		By("opening the circuit breaker again when the trial call fails")
+		time.Sleep(150 * time.Millisecond)
+		Expect(hostClient.Ping(context.Background())).To(MatchError(ContainSubstring("500")))
+		Expect(hostClient.Ping(context.Background())).To(MatchError(KindAPICircuitOpenError))
+
+		By("closing the circuit breaker when the trial call succeeds")
+		failing = false
+		time.Sleep(150 * time.Millisecond)
+		Expect(hostClient.Ping(context.Background())).To(Succeed())
+		Expect(hostClient.breaker.state).To(Equal(circuitBreakerClosed))
+		Expect(testutil.ToFloat64(kindAPICircuitBreakerState.WithLabelValues(server.URL))).To(Equal(float64(circuitBreakerClosed)))
+	})
 })
@@ -18,30 +18,84 @@ package controllers
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	"net"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"strconv"
 	"time"
 
 	infrastructurev1alpha1 "cluster-api-provider-kind/api/v1alpha1"
 )
 
+const (
+	controlPlaneDialTimeout = 3 * time.Second
+	hostCapacityRequeue     = 30 * time.Second
+	defaultFailureMessage   = "Kind cluster failed"
+
+	defaultCheckInterval         = time.Minute
+	provisioningRetryInterval    = 10 * time.Second
+	provisioningMaxRetryInterval = 5 * time.Minute
+
+	// kindClusterTTLGracePeriod is the time the Kind Wrapper API keeps a Kind cluster past the expiration of its KindCluster,
+	// so that the controller deletes the KindCluster and its owner Cluster before the Kind cluster disappears
+	kindClusterTTLGracePeriod = 5 * time.Minute
+)
+
 // KindClusterReconciler reconciles a KindCluster object
 type KindClusterReconciler struct {
 	client.Client
 	Scheme     *runtime.Scheme
 	KindClient *KindClient
+	// Recorder records the lifecycle events of KindClusters, the recorder of the manager is used if not set
+	Recorder record.EventRecorder
+	// CheckInterval is the interval of checks of running Kind clusters, defaultCheckInterval is used if not set
+	CheckInterval time.Duration
+	// WatchFilterValue limits the reconciliation to KindClusters, Clusters and KindHosts with the cluster.x-k8s.io/watch-filter label of this value
+	WatchFilterValue string
+	// MaxConcurrentReconciles is the number of KindClusters reconciled concurrently, one if not set
+	MaxConcurrentReconciles int
+	// ManagerID identifies the management cluster in the owners of the Kind clusters recorded by the Kind Wrapper APIs
+	ManagerID string
+	// CredentialsNamespace is the namespace of the provider, the credentials Secrets of the KindHosts are read from it only
+	CredentialsNamespace string
+
+	hostClients *kindHostClients
 }
 
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindhosts,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=create
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -56,8 +110,9 @@ func (r *KindClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	var kindCluster infrastructurev1alpha1.KindCluster
 	if err := r.Get(ctx, req.NamespacedName, &kindCluster); err != nil {
 		if errors.IsNotFound(err) {
-			err = r.KindClient.DeleteCluster(req.Namespace, req.Name)
-			return ctrl.Result{}, err
+			// The Kind cluster is deleted by the finalizer only, a KindCluster removed without it
+			// (e.g. moved to another management cluster by clusterctl) leaves the Kind cluster running
+			return ctrl.Result{}, nil
 		}
 		logger.Error(err, fmt.Sprintf("Failed to retrieve cluster %s", clusterName))
 		return ctrl.Result{}, err
@@ -70,39 +125,100 @@ func (r *KindClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// Leave paused clusters untouched, e.g. while they are being moved by clusterctl, apart from handing over the owner of the Kind cluster
+	if isPaused(ownerCluster, &kindCluster) {
+		logger.Info(fmt.Sprintf("Reconciliation of cluster %s is paused", clusterName))
+		return r.reconcilePaused(ctx, &kindCluster)
+	}
+
 	// Get Patch helper to update the cluster
 	helper, err := patch.NewHelper(&kindCluster, r.Client)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// Adopt the existing Kind cluster named by the annotation instead of creating a new one
+	if !kindCluster.IsBeingDeleted() && kindCluster.IsAdopting() {
+		return r.reconcileAdoption(ctx, helper, &kindCluster, ownerCluster)
+	}
+
+	// Select a KindHost for a Kind cluster without a host reference until the Kind cluster gets created
+	if !kindCluster.IsBeingDeleted() && needsScheduling(&kindCluster) {
+		spec, err := r.resolveRawConfig(ctx, &kindCluster)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Failed to resolve raw config of cluster %s", clusterName))
+			conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.WaitingForRawConfigReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{}, kerrors.NewAggregate([]error{err, r.patchKindCluster(ctx, helper, &kindCluster)})
+		}
+		scheduled, err := r.reconcileScheduling(ctx, &kindCluster, spec)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Failed to schedule cluster %s", clusterName))
+			return ctrl.Result{}, err
+		} else if !scheduled {
+			logger.Info(fmt.Sprintf("No KindHost is suitable for cluster %s", clusterName))
+			return ctrl.Result{RequeueAfter: hostCapacityRequeue}, r.patchKindCluster(ctx, helper, &kindCluster)
+		}
+	}
+
+	// Get a client of the Kind Wrapper API the Kind cluster was created on
+	kindClient, err := r.kindClientForHost(ctx, appliedHostName(&kindCluster))
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to get Kind Wrapper API client of cluster %s", clusterName))
+		conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.KindHostUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, kerrors.NewAggregate([]error{err, r.patchKindCluster(ctx, helper, &kindCluster)})
+	}
+
 	// Retrieve observed cluster state
-	observedStatus, err := r.KindClient.GetClusterStatus(req.Namespace, req.Name)
+	observedStatus, err := kindClient.GetClusterStatus(ctx, kindClusterName(&kindCluster))
 	clusterNotFound := err == KindClusterNotFoundError
 	if err != nil && !clusterNotFound {
-		return ctrl.Result{}, err
+		logger.Error(err, fmt.Sprintf("Failed to retrieve Kind cluster status of cluster %s", clusterName))
+		return r.reconcileKindAPIError(ctx, helper, &kindCluster, err)
 	}
+	conditions.MarkTrue(&kindCluster, infrastructurev1alpha1.KindAPIAvailableCondition)
 
 	// If the resource is being deleted, handle the finalizer
 	if kindCluster.IsBeingDeleted() {
 		if kindCluster.HasFinalizer(infrastructurev1alpha1.KindClusterFinalizerName) {
-			// Make sure the Kind cluster is deleted
-			if !clusterNotFound {
-				err := r.KindClient.DeleteCluster(kindCluster.Namespace, kindCluster.Name)
+			// Make sure the Kind cluster is deleted, unless the deletion policy keeps it running
+			if kindCluster.KeepsKindCluster() && !clusterNotFound {
+				logger.Info(fmt.Sprintf("Keeping Kind cluster of cluster %s due to the %s deletion policy", clusterName, kindCluster.Spec.DeletionPolicy))
+				// Release the kept Kind cluster, so that it is not collected as an orphan
+				if err := releaseKindCluster(ctx, kindClient, &kindCluster); err != nil {
+					logger.Error(err, fmt.Sprintf("Failed to release Kind cluster of cluster %s", clusterName))
+					return ctrl.Result{}, err
+				}
+				r.recordEvent(ctx, &kindCluster, corev1.EventTypeNormal, KindClusterRetainedEvent, "Keeping Kind cluster due to the %s deletion policy", kindCluster.Spec.DeletionPolicy)
+			} else if !clusterNotFound {
+				err := kindClient.DeleteCluster(ctx, kindClusterName(&kindCluster))
 				if err != nil {
 					logger.Error(err, fmt.Sprintf("Failed to delete Kind cluster %s", clusterName))
+					r.recordEvent(ctx, &kindCluster, corev1.EventTypeWarning, KindAPIErrorEvent, "Failed to delete Kind cluster: %s", err)
+					return ctrl.Result{}, err
+				}
+				if kindCluster.Status.State != infrastructurev1alpha1.KindClusterStateDeleting {
+					r.recordEvent(ctx, &kindCluster, corev1.EventTypeNormal, KindClusterDeletingEvent, "Deleting Kind cluster")
+				}
+				conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.KindClusterDeletingReason, clusterv1.ConditionSeverityInfo, "")
+				kindCluster.Status.State = infrastructurev1alpha1.KindClusterStateDeleting
+				if err := r.patchKindCluster(ctx, helper, &kindCluster); err != nil {
+					logger.Error(err, fmt.Sprintf("Failed to update cluster %s", clusterName))
 					return ctrl.Result{}, err
 				}
 				return ctrl.Result{RequeueAfter: time.Second}, nil
 			}
-			// Delete the owner cluster, unless it is already being deleted
-			if ownerCluster != nil && ownerCluster.ObjectMeta.DeletionTimestamp.IsZero() {
+			// Delete the owner cluster, unless it is already being deleted or retained by the deletion policy
+			if ownerCluster != nil && ownerCluster.ObjectMeta.DeletionTimestamp.IsZero() &&
+				kindCluster.Spec.DeletionPolicy != infrastructurev1alpha1.KindClusterDeletionPolicyRetain {
 				err := r.Client.Delete(ctx, ownerCluster)
 				if err != nil {
 					logger.Error(err, fmt.Sprintf("Failed to handle finalizer in cluster %s", clusterName))
 					return ctrl.Result{}, err
 				}
 			}
+			if kindCluster.Status.State == infrastructurev1alpha1.KindClusterStateDeleting {
+				r.recordEvent(ctx, &kindCluster, corev1.EventTypeNormal, KindClusterDeletedEvent, "Kind cluster deleted")
+			}
 			kindCluster.RemoveFinalizer(infrastructurev1alpha1.KindClusterFinalizerName)
 			err := helper.Patch(ctx, &kindCluster)
 			return ctrl.Result{}, err
@@ -115,36 +231,257 @@ func (r *KindClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		kindCluster.AddFinalizer(infrastructurev1alpha1.KindClusterFinalizerName)
 	}
 
+	// Record the name of the Kind cluster, so that it does not change once the KindCluster has been provisioned
+	if kindCluster.Status.KindClusterName == "" {
+		kindCluster.Status.KindClusterName = kindClusterName(&kindCluster)
+	}
+
+	// Delete the KindCluster and its owner Cluster once the TTL expires
+	expired, err := r.reconcileExpiration(ctx, kindClient, &kindCluster, ownerCluster, clusterNotFound)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to reconcile TTL of cluster %s", clusterName))
+		return ctrl.Result{}, kerrors.NewAggregate([]error{err, r.patchKindCluster(ctx, helper, &kindCluster)})
+	} else if expired {
+		return ctrl.Result{}, nil
+	}
+
+	// Start over a failed Kind cluster on request, the annotation is removed in any case
+	if _, ok := kindCluster.Annotations[infrastructurev1alpha1.KindClusterRetryAnnotation]; ok {
+		delete(kindCluster.Annotations, infrastructurev1alpha1.KindClusterRetryAnnotation)
+		if kindCluster.Status.State == infrastructurev1alpha1.KindClusterStateFailed {
+			logger.Info(fmt.Sprintf("Retrying failed cluster %s", clusterName))
+			r.recordEvent(ctx, &kindCluster, corev1.EventTypeNormal, KindClusterRetryRequestedEvent, "Retry of the failed Kind cluster requested")
+			if !clusterNotFound {
+				if err := kindClient.DeleteCluster(ctx, kindClusterName(&kindCluster)); err != nil {
+					logger.Error(err, fmt.Sprintf("Failed to delete Kind cluster %s", clusterName))
+					return r.reconcileKindAPIError(ctx, helper, &kindCluster, err)
+				}
+			}
+			kindCluster.Status.Provisioning = nil
+			kindCluster.Status.State = infrastructurev1alpha1.KindClusterStatePending
+			kindCluster.Status.Ready = false
+			kindCluster.Status.FailureReason = nil
+			kindCluster.Status.FailureMessage = nil
+			conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.WaitingForRetryReason, clusterv1.ConditionSeverityInfo, "retry requested")
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, r.patchKindCluster(ctx, helper, &kindCluster)
+		}
+	}
+
+	// Schedule the Kind cluster again in case it failed on the selected KindHost and rescheduling is enabled
+	if shouldReschedule(&kindCluster) {
+		failedHost := kindCluster.Status.Host
+		logger.Info(fmt.Sprintf("Cluster %s failed on KindHost %s, rescheduling", clusterName, failedHost))
+		r.recordEvent(ctx, &kindCluster, corev1.EventTypeNormal, KindClusterReschedulingEvent, "Kind cluster failed on KindHost %s, rescheduling", failedHost)
+		if !clusterNotFound {
+			if err := kindClient.DeleteCluster(ctx, kindClusterName(&kindCluster)); err != nil {
+				logger.Error(err, fmt.Sprintf("Failed to delete Kind cluster %s", clusterName))
+				return ctrl.Result{}, err
+			}
+		}
+		if kindCluster.Status.Scheduling == nil {
+			kindCluster.Status.Scheduling = &infrastructurev1alpha1.KindClusterSchedulingStatus{}
+		}
+		kindCluster.Status.Scheduling.FailedHosts = append(kindCluster.Status.Scheduling.FailedHosts, failedHost)
+		kindCluster.Status.AppliedSpecHash = ""
+		kindCluster.Status.Host = ""
+		kindCluster.Status.Provisioning = nil
+		delete(kindCluster.Annotations, infrastructurev1alpha1.KindClusterHostAnnotation)
+		kindCluster.Status.State = infrastructurev1alpha1.KindClusterStatePending
+		kindCluster.Status.Ready = false
+		kindCluster.Status.FailureReason = nil
+		kindCluster.Status.FailureMessage = nil
+		message := fmt.Sprintf("Kind cluster failed on KindHost %s", failedHost)
+		conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindHostScheduledCondition, infrastructurev1alpha1.ReschedulingReason, clusterv1.ConditionSeverityInfo, message)
+		conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.ReschedulingReason, clusterv1.ConditionSeverityInfo, message)
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, r.patchKindCluster(ctx, helper, &kindCluster)
+	}
+
 	// In case the cluster is in the Failed state, stop the reconciliation
-	if kindCluster.Status.State == infrastructurev1alpha1.KindClusterStateFailed {
+	// unless the spec has changed since and the Kind cluster can be recreated
+	if kindCluster.Status.State == infrastructurev1alpha1.KindClusterStateFailed &&
+		(kindCluster.Spec.UpdateStrategy != infrastructurev1alpha1.KindClusterUpdateStrategyRecreate ||
+			kindCluster.Generation == kindCluster.Status.ObservedGeneration) {
+		// Failed KindClusters with a TTL are reconciled again once they expire
+		if kindCluster.Status.ExpiresAt != nil {
+			return ctrl.Result{RequeueAfter: requeueBeforeExpiration(&kindCluster, 0)}, helper.Patch(ctx, &kindCluster)
+		}
 		return ctrl.Result{}, nil
 	}
 
+	// Detect changes of the spec applied to an existing Kind cluster
+	if kindCluster.Status.AppliedSpecHash != "" && kindCluster.Status.State != infrastructurev1alpha1.KindClusterStateRecreating &&
+		kindCluster.Status.State != infrastructurev1alpha1.KindClusterStatePending && kindCluster.Status.State != "" {
+		spec, err := r.resolveRawConfig(ctx, &kindCluster)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Failed to resolve raw config of cluster %s", clusterName))
+			return ctrl.Result{}, err
+		}
+		// Scheduled Kind clusters stay on the selected KindHost, only a changed host reference moves them
+		hostChanged := kindCluster.Spec.HostRef != nil && kindCluster.Status.Host != kindCluster.HostName()
+		if spec.KindConfigHash() == kindCluster.Status.AppliedSpecHash && !hostChanged {
+			conditions.MarkTrue(&kindCluster, infrastructurev1alpha1.KindClusterSpecAppliedCondition)
+		} else if kindCluster.Spec.UpdateStrategy == infrastructurev1alpha1.KindClusterUpdateStrategyRecreate {
+			logger.Info(fmt.Sprintf("Spec of cluster %s changed, recreating Kind cluster", clusterName))
+			r.recordEvent(ctx, &kindCluster, corev1.EventTypeNormal, KindClusterRecreatingEvent, "Spec changed, recreating Kind cluster")
+			if !clusterNotFound {
+				if err := kindClient.DeleteCluster(ctx, kindClusterName(&kindCluster)); err != nil {
+					logger.Error(err, fmt.Sprintf("Failed to delete Kind cluster %s", clusterName))
+					return ctrl.Result{}, err
+				}
+			}
+			// The recreated Kind cluster may expose the control plane on a different endpoint
+			kindCluster.Spec.ControlPlaneEndpoint = infrastructurev1alpha1.KindClusterControlPlaneEndpoint{}
+			kindCluster.Status.Provisioning = nil
+			kindCluster.Status.State = infrastructurev1alpha1.KindClusterStateRecreating
+			kindCluster.Status.Ready = false
+			kindCluster.Status.FailureReason = nil
+			kindCluster.Status.FailureMessage = nil
+			conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterSpecAppliedCondition, infrastructurev1alpha1.KindClusterRecreatingReason, clusterv1.ConditionSeverityInfo, "")
+			conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.KindClusterRecreatingReason, clusterv1.ConditionSeverityInfo, "")
+			conditions.Delete(&kindCluster, infrastructurev1alpha1.ControlPlaneReachableCondition)
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, r.patchKindCluster(ctx, helper, &kindCluster)
+		} else {
+			conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterSpecAppliedCondition, infrastructurev1alpha1.KindClusterSpecDriftedReason, clusterv1.ConditionSeverityWarning,
+				"spec changed after the Kind cluster was created, but the Immutable update strategy does not allow to apply it")
+		}
+	}
+
+	// Wait for the deletion of the Kind cluster being recreated
+	if kindCluster.Status.State == infrastructurev1alpha1.KindClusterStateRecreating && !clusterNotFound {
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, r.patchKindCluster(ctx, helper, &kindCluster)
+	}
+
 	// Update cluster state
 	result := ctrl.Result{}
 	if observedStatus.State == KindStateRunning {
 		if !kindCluster.HasControlPlaneEndpoint() {
 			kindCluster.AddControlPlaneEndpoint(observedStatus.Host, observedStatus.Port)
 		}
+		previousState := kindCluster.Status.State
 		kindCluster.Status.State = infrastructurev1alpha1.KindClusterStateRunning
 		kindCluster.Status.Ready = true
+		r.recordReadyEvent(ctx, &kindCluster, previousState)
+		observeProvisioningDuration(&kindCluster, previousState)
+		if provisioning := kindCluster.Status.Provisioning; provisioning != nil {
+			provisioning.StartTime = nil
+			provisioning.NextAttemptTime = nil
+		}
+		if kindCluster.Status.AppliedSpecHash == "" {
+			// Kind clusters created before the hash was recorded or moved without the status are assumed to match the current spec
+			if spec, err := r.resolveRawConfig(ctx, &kindCluster); err == nil {
+				logger.Info(fmt.Sprintf("Adopting running Kind cluster of cluster %s", clusterName))
+				// A moved KindCluster has got a new UID, which has to be recorded as the owner of its Kind cluster
+				if err := setKindClusterOwner(ctx, kindClient, kindClusterName(&kindCluster), kindClusterOwner(&kindCluster, r.ManagerID)); err != nil {
+					logger.Error(err, fmt.Sprintf("Failed to record owner of Kind cluster of cluster %s", clusterName))
+					return r.reconcileKindAPIError(ctx, helper, &kindCluster, err)
+				}
+				kindCluster.Status.Host = appliedHostName(&kindCluster)
+				kindCluster.Status.AppliedSpecHash = spec.KindConfigHash()
+				conditions.MarkTrue(&kindCluster, infrastructurev1alpha1.KindClusterSpecAppliedCondition)
+			}
+		}
+		if kindCluster.Status.OwnerHandedOver {
+			// The owner was handed over while the KindCluster was paused, the identity of the management cluster is recorded again
+			if err := setKindClusterOwner(ctx, kindClient, kindClusterName(&kindCluster), kindClusterOwner(&kindCluster, r.ManagerID)); err != nil {
+				logger.Error(err, fmt.Sprintf("Failed to record owner of Kind cluster of cluster %s", clusterName))
+				return r.reconcileKindAPIError(ctx, helper, &kindCluster, err)
+			}
+			kindCluster.Status.OwnerHandedOver = false
+		}
+		conditions.MarkTrue(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition)
+		if conditions.IsTrue(&kindCluster, infrastructurev1alpha1.KindClusterLostCondition) {
+			conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterLostCondition, infrastructurev1alpha1.KindClusterRecoveredReason, clusterv1.ConditionSeverityInfo, "Kind cluster is running again")
+		}
+		r.reconcileControlPlaneReachable(&kindCluster)
+		// Keep checking the running Kind cluster, so that it is detected once lost
+		result.RequeueAfter = r.checkInterval()
+	} else if clusterNotFound && kindCluster.Status.State == infrastructurev1alpha1.KindClusterStateRunning {
+		return r.reconcileLostCluster(ctx, helper, &kindCluster)
 	} else if observedStatus.State == KindStateFailed {
-		kindCluster.Status.State = infrastructurev1alpha1.KindClusterStateFailed
-		kindCluster.Status.Ready = false
+		if provisioning := kindCluster.Status.Provisioning; provisioning != nil && provisioning.StartTime == nil {
+			// The failed attempt has been recorded already, make sure the failed Kind cluster is deleted before the next one
+			if err := kindClient.DeleteCluster(ctx, kindClusterName(&kindCluster)); err != nil {
+				logger.Error(err, fmt.Sprintf("Failed to delete Kind cluster %s", clusterName))
+				return r.reconcileKindAPIError(ctx, helper, &kindCluster, err)
+			}
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, r.patchKindCluster(ctx, helper, &kindCluster)
+		}
+		message := observedStatus.Message
+		if message == "" {
+			message = defaultFailureMessage
+		}
+		return r.reconcileFailedAttempt(ctx, helper, kindClient, &kindCluster, infrastructurev1alpha1.KindClusterFailedReason, message)
 	} else {
+		if provisioning := kindCluster.Status.Provisioning; !clusterNotFound && provisioning != nil && provisioning.StartTime != nil &&
+			time.Since(provisioning.StartTime.Time) > kindCluster.ProvisioningTimeout() {
+			message := fmt.Sprintf("Kind cluster is not running after %s", kindCluster.ProvisioningTimeout())
+			return r.reconcileFailedAttempt(ctx, helper, kindClient, &kindCluster, infrastructurev1alpha1.ProvisioningTimeoutReason, message)
+		}
+		if provisioning := kindCluster.Status.Provisioning; clusterNotFound && provisioning != nil && provisioning.NextAttemptTime != nil &&
+			time.Now().Before(provisioning.NextAttemptTime.Time) {
+			return ctrl.Result{RequeueAfter: time.Until(provisioning.NextAttemptTime.Time)}, r.patchKindCluster(ctx, helper, &kindCluster)
+		}
 		if clusterNotFound && observedStatus.State != KindStatePending {
-			err = r.KindClient.CreateCluster(req.Namespace, req.Name, kindCluster.Spec)
+			spec, err := r.resolveRawConfig(ctx, &kindCluster)
 			if err != nil {
-				logger.Error(err, fmt.Sprintf("Failed to start cluster %s", clusterName))
+				logger.Error(err, fmt.Sprintf("Failed to resolve raw config of cluster %s", clusterName))
+				conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.WaitingForRawConfigReason, clusterv1.ConditionSeverityWarning, err.Error())
+				return ctrl.Result{}, kerrors.NewAggregate([]error{err, r.patchKindCluster(ctx, helper, &kindCluster)})
+			}
+			hostName := appliedHostName(&kindCluster)
+			createClient, err := r.kindClientForHost(ctx, hostName)
+			if err != nil {
+				logger.Error(err, fmt.Sprintf("Failed to get Kind Wrapper API client of cluster %s", clusterName))
+				conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.KindHostUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
+				return ctrl.Result{}, kerrors.NewAggregate([]error{err, r.patchKindCluster(ctx, helper, &kindCluster)})
+			}
+			hasCapacity, err := r.hostHasCapacity(ctx, &kindCluster, spec)
+			if err != nil {
+				logger.Error(err, fmt.Sprintf("Failed to check host capacity of cluster %s", clusterName))
 				return ctrl.Result{}, err
+			} else if !hasCapacity {
+				logger.Info(fmt.Sprintf("Host %s of cluster %s is full", kindCluster.HostName(), clusterName))
+				conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.WaitingForHostCapacityReason, clusterv1.ConditionSeverityInfo,
+					"KindHost %s has no capacity left", kindCluster.HostName())
+				return ctrl.Result{RequeueAfter: hostCapacityRequeue}, r.patchKindCluster(ctx, helper, &kindCluster)
+			}
+			owner := kindClusterOwner(&kindCluster, r.ManagerID)
+			if expiresAt := kindCluster.Status.ExpiresAt; expiresAt != nil {
+				owner.TTL = time.Until(expiresAt.Time) + kindClusterTTLGracePeriod
+			}
+			err = createClient.CreateCluster(ctx, kindCluster.Status.KindClusterName, owner, spec)
+			if goerrors.Is(err, KindAPICircuitOpenError) {
+				return r.reconcileKindAPIError(ctx, helper, &kindCluster, err)
+			}
+			if err == nil {
+				r.recordEvent(ctx, &kindCluster, corev1.EventTypeNormal, KindClusterCreationRequestedEvent, "Creation of Kind cluster requested")
+			} else {
+				r.recordEvent(ctx, &kindCluster, corev1.EventTypeWarning, KindClusterCreationFailedEvent, "Failed to create Kind cluster: %s", err)
+			}
+			startProvisioningAttempt(&kindCluster)
+			kindCluster.Status.AppliedSpecHash = spec.KindConfigHash()
+			kindCluster.Status.Host = hostName
+			setHostAnnotation(&kindCluster, hostName)
+			conditions.MarkTrue(&kindCluster, infrastructurev1alpha1.KindClusterSpecAppliedCondition)
+			if goerrors.Is(err, KindClusterInvalidConfigError) || goerrors.Is(err, infrastructurev1alpha1.InvalidKindConfigError) {
+				// The configuration will not be accepted until the spec changes, so there is no point in retrying
+				logger.Error(err, fmt.Sprintf("Invalid config of cluster %s", clusterName))
+				kindCluster.SetFailure(capierrors.InvalidConfigurationClusterError, err.Error())
+				conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.KindClusterInvalidConfigReason, clusterv1.ConditionSeverityError, err.Error())
+				return ctrl.Result{}, r.patchKindCluster(ctx, helper, &kindCluster)
+			} else if err != nil {
+				logger.Error(err, fmt.Sprintf("Failed to start cluster %s", clusterName))
+				return r.reconcileFailedAttempt(ctx, helper, createClient, &kindCluster, infrastructurev1alpha1.KindClusterCreationFailedReason, err.Error())
 			}
 		}
 		kindCluster.Status.State = infrastructurev1alpha1.KindClusterStatePending
 		kindCluster.Status.Ready = false
+		conditions.MarkFalse(&kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.WaitingForKindClusterReason, clusterv1.ConditionSeverityInfo, "")
 		result.RequeueAfter = 5 * time.Second
 	}
+	result.RequeueAfter = requeueBeforeExpiration(&kindCluster, result.RequeueAfter)
 
-	err = helper.Patch(ctx, &kindCluster)
+	err = r.patchKindCluster(ctx, helper, &kindCluster)
 	if err != nil {
 		logger.Error(err, fmt.Sprintf("Failed to update cluster %s", clusterName))
 		return ctrl.Result{}, err
@@ -153,9 +490,659 @@ func (r *KindClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return result, err
 }
 
+// patchKindCluster summarizes the conditions into the Ready condition, records the observed generation and patches the KindCluster
+func (r *KindClusterReconciler) patchKindCluster(ctx context.Context, helper *patch.Helper, kindCluster *infrastructurev1alpha1.KindCluster) error {
+	conditions.SetSummary(kindCluster, conditions.WithConditions(infrastructurev1alpha1.KindClusterProvisionedCondition))
+	kindCluster.Status.ObservedGeneration = kindCluster.Generation
+	return helper.Patch(ctx, kindCluster, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+		clusterv1.ReadyCondition,
+		infrastructurev1alpha1.KindClusterProvisionedCondition,
+		infrastructurev1alpha1.KindClusterSpecAppliedCondition,
+		infrastructurev1alpha1.ControlPlaneReachableCondition,
+		infrastructurev1alpha1.KindHostScheduledCondition,
+		infrastructurev1alpha1.KindAPIAvailableCondition,
+		infrastructurev1alpha1.KindClusterLostCondition,
+	}})
+}
+
+// reconcileLostCluster handles a running Kind cluster which no longer exists according to the recovery policy
+// The Kind cluster is created again with the Recreate policy, the KindCluster is failed with the Fail policy
+func (r *KindClusterReconciler) reconcileLostCluster(ctx context.Context, helper *patch.Helper, kindCluster *infrastructurev1alpha1.KindCluster) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	clusterName := client.ObjectKeyFromObject(kindCluster).String()
+	message := fmt.Sprintf("Kind cluster %s no longer exists", kindClusterName(kindCluster))
+	conditions.Delete(kindCluster, infrastructurev1alpha1.ControlPlaneReachableCondition)
+	conditions.Set(kindCluster, &clusterv1.Condition{
+		Type:    infrastructurev1alpha1.KindClusterLostCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrastructurev1alpha1.KindClusterLostReason,
+		Message: message,
+	})
+	if kindCluster.Spec.RecoveryPolicy == infrastructurev1alpha1.KindClusterRecoveryPolicyFail {
+		logger.Info(fmt.Sprintf("Kind cluster of cluster %s lost, failing due to the %s recovery policy", clusterName, kindCluster.Spec.RecoveryPolicy))
+		r.recordEvent(ctx, kindCluster, corev1.EventTypeWarning, KindClusterLostEvent, "%s, failing due to the %s recovery policy", message, kindCluster.Spec.RecoveryPolicy)
+		kindCluster.SetFailure(capierrors.UpdateClusterError, message)
+		conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.KindClusterLostReason, clusterv1.ConditionSeverityError, message)
+		return ctrl.Result{}, r.patchKindCluster(ctx, helper, kindCluster)
+	}
+
+	logger.Info(fmt.Sprintf("Kind cluster of cluster %s lost, recreating", clusterName))
+	r.recordEvent(ctx, kindCluster, corev1.EventTypeWarning, KindClusterLostEvent, "%s, recreating", message)
+	// The recreated Kind cluster may expose the control plane on a different endpoint
+	kindCluster.Spec.ControlPlaneEndpoint = infrastructurev1alpha1.KindClusterControlPlaneEndpoint{}
+	kindCluster.Status.Provisioning = nil
+	kindCluster.Status.State = infrastructurev1alpha1.KindClusterStatePending
+	kindCluster.Status.Ready = false
+	conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.KindClusterLostReason, clusterv1.ConditionSeverityWarning, "%s, recreating", message)
+	return ctrl.Result{RequeueAfter: time.Second}, r.patchKindCluster(ctx, helper, kindCluster)
+}
+
+// checkInterval returns the interval of checks of running Kind clusters
+func (r *KindClusterReconciler) checkInterval() time.Duration {
+	if r.CheckInterval > 0 {
+		return r.CheckInterval
+	}
+	return defaultCheckInterval
+}
+
+// reconcileExpiration records the expiration time of a KindCluster with a TTL and the time left until it expires
+// The Kind Wrapper API is told to delete the existing Kind cluster a grace period after the expiration time whenever it changes,
+// in case the KindCluster is not deleted by then, e.g. while the controller is down
+// True is returned once the KindCluster expired and the deletion of the KindCluster and its owner Cluster was requested,
+// the owner Cluster is left untouched by the Retain deletion policy like on any other deletion of the KindCluster
+func (r *KindClusterReconciler) reconcileExpiration(ctx context.Context, kindClient *KindClient, kindCluster *infrastructurev1alpha1.KindCluster, ownerCluster *clusterv1.Cluster, clusterNotFound bool) (bool, error) {
+	logger := log.FromContext(ctx)
+	clusterName := client.ObjectKeyFromObject(kindCluster).String()
+
+	// The start of the TTL is recorded, so that it does not start over once the KindCluster is moved by clusterctl
+	if _, ok := kindCluster.Annotations[infrastructurev1alpha1.KindClusterTTLStartAnnotation]; !ok && kindCluster.Spec.TTL != nil {
+		annotations.AddAnnotations(kindCluster, map[string]string{
+			infrastructurev1alpha1.KindClusterTTLStartAnnotation: kindCluster.CreationTimestamp.UTC().Format(time.RFC3339),
+		})
+	}
+
+	expiresAt, err := kindCluster.ExpirationTime()
+	if err != nil {
+		// The recorded expiration time is kept until the annotation is fixed, so that a typo does not shorten the TTL
+		logger.Error(err, fmt.Sprintf("Failed to compute expiration time of cluster %s", clusterName))
+		r.recordEvent(ctx, kindCluster, corev1.EventTypeWarning, KindClusterInvalidTTLExtensionEvent, "Failed to compute expiration time: %s", err)
+		return false, nil
+	}
+
+	if expiresAt != nil && !time.Now().Before(expiresAt.Time) {
+		logger.Info(fmt.Sprintf("Cluster %s expired, deleting", clusterName))
+		r.recordEvent(ctx, kindCluster, corev1.EventTypeNormal, KindClusterExpiredEvent, "TTL expired at %s, deleting the KindCluster", expiresAt.UTC().Format(time.RFC3339))
+		if ownerCluster != nil && ownerCluster.ObjectMeta.DeletionTimestamp.IsZero() &&
+			kindCluster.Spec.DeletionPolicy != infrastructurev1alpha1.KindClusterDeletionPolicyRetain {
+			if err := r.Client.Delete(ctx, ownerCluster); err != nil && !errors.IsNotFound(err) {
+				return false, err
+			}
+		}
+		if err := r.Client.Delete(ctx, kindCluster); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		return true, nil
+	}
+
+	// The Kind cluster to be created gets the TTL with the create request
+	if !clusterNotFound && !kindCluster.Status.ExpiresAt.Equal(expiresAt) {
+		ttl := time.Duration(0)
+		if expiresAt != nil {
+			ttl = time.Until(expiresAt.Time) + kindClusterTTLGracePeriod
+		}
+		if err := kindClient.SetClusterTTL(ctx, kindClusterName(kindCluster), ttl); err != nil && err != KindClusterNotFoundError {
+			return false, err
+		}
+	}
+	kindCluster.Status.ExpiresAt = expiresAt
+	kindCluster.Status.RemainingTTL = ""
+	if expiresAt != nil {
+		kindCluster.Status.RemainingTTL = duration.HumanDuration(time.Until(expiresAt.Time))
+	}
+	return false, nil
+}
+
+// requeueBeforeExpiration shortens the requeue interval of a KindCluster with a TTL, so that it is reconciled once it expires
+// A zero interval, i.e. no requeue, is replaced as well
+func requeueBeforeExpiration(kindCluster *infrastructurev1alpha1.KindCluster, requeueAfter time.Duration) time.Duration {
+	if kindCluster.Status.ExpiresAt == nil {
+		return requeueAfter
+	}
+	untilExpiration := time.Until(kindCluster.Status.ExpiresAt.Time)
+	if untilExpiration < time.Second {
+		untilExpiration = time.Second
+	}
+	if requeueAfter == 0 || untilExpiration < requeueAfter {
+		return untilExpiration
+	}
+	return requeueAfter
+}
+
+// reconcileFailedAttempt records a failed attempt to create the Kind cluster
+// The failed Kind cluster is deleted and created again after an exponential backoff until the retries are exhausted,
+// the KindCluster is failed afterwards
+func (r *KindClusterReconciler) reconcileFailedAttempt(ctx context.Context, helper *patch.Helper, kindClient *KindClient, kindCluster *infrastructurev1alpha1.KindCluster, reason, message string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	clusterName := client.ObjectKeyFromObject(kindCluster).String()
+	if kindCluster.Status.Provisioning == nil {
+		kindCluster.Status.Provisioning = &infrastructurev1alpha1.KindClusterProvisioningStatus{Attempts: 1}
+	}
+	provisioning := kindCluster.Status.Provisioning
+	provisioning.StartTime = nil
+	provisioning.NextAttemptTime = nil
+	provisioning.LastFailure = message
+
+	maxRetries := kindCluster.ProvisioningMaxRetries()
+	if provisioning.Attempts > maxRetries {
+		if maxRetries > 0 {
+			reason = infrastructurev1alpha1.ProvisioningRetriesExceededReason
+			message = fmt.Sprintf("%s, giving up after %d attempts", message, provisioning.Attempts)
+		}
+		logger.Info(fmt.Sprintf("Cluster %s failed: %s", clusterName, message))
+		r.recordEvent(ctx, kindCluster, corev1.EventTypeWarning, KindClusterProvisioningFailedEvent, "Kind cluster failed: %s", message)
+		kindCluster.SetFailure(capierrors.CreateClusterError, message)
+		conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, reason, clusterv1.ConditionSeverityError, message)
+		return ctrl.Result{}, r.patchKindCluster(ctx, helper, kindCluster)
+	}
+
+	// The failed Kind cluster has to be deleted before it can be created again
+	if err := kindClient.DeleteCluster(ctx, kindClusterName(kindCluster)); err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to delete Kind cluster %s", clusterName))
+		return r.reconcileKindAPIError(ctx, helper, kindCluster, err)
+	}
+	backoff := provisioningBackoff(provisioning.Attempts)
+	nextAttemptTime := metav1.NewTime(time.Now().Add(backoff))
+	provisioning.NextAttemptTime = &nextAttemptTime
+	kindCluster.Status.State = infrastructurev1alpha1.KindClusterStatePending
+	kindCluster.Status.Ready = false
+	logger.Info(fmt.Sprintf("Attempt %d to create cluster %s failed, retrying in %s: %s", provisioning.Attempts, clusterName, backoff, message))
+	r.recordEvent(ctx, kindCluster, corev1.EventTypeWarning, KindClusterRetryingEvent, "Attempt %d of %d failed, retrying in %s: %s", provisioning.Attempts, maxRetries+1, backoff, message)
+	conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.WaitingForRetryReason, clusterv1.ConditionSeverityWarning,
+		"attempt %d of %d failed (%s): %s", provisioning.Attempts, maxRetries+1, reason, message)
+	return ctrl.Result{RequeueAfter: backoff}, r.patchKindCluster(ctx, helper, kindCluster)
+}
+
+// startProvisioningAttempt records the start of an attempt to create the Kind cluster
+// A Kind cluster created again while the attempt is in progress, e.g. because it vanished, is a part of the same attempt
+func startProvisioningAttempt(kindCluster *infrastructurev1alpha1.KindCluster) {
+	if kindCluster.Status.Provisioning == nil {
+		kindCluster.Status.Provisioning = &infrastructurev1alpha1.KindClusterProvisioningStatus{}
+	}
+	if kindCluster.Status.Provisioning.StartTime != nil {
+		return
+	}
+	now := metav1.Now()
+	kindCluster.Status.Provisioning.Attempts++
+	kindCluster.Status.Provisioning.StartTime = &now
+	kindCluster.Status.Provisioning.NextAttemptTime = nil
+}
+
+// provisioningBackoff returns the delay of the next attempt to create the Kind cluster after the specified number of failed attempts
+func provisioningBackoff(attempts int32) time.Duration {
+	backoff := provisioningRetryInterval
+	for i := int32(1); i < attempts && backoff < provisioningMaxRetryInterval; i++ {
+		backoff *= 2
+	}
+	if backoff > provisioningMaxRetryInterval {
+		return provisioningMaxRetryInterval
+	}
+	return backoff
+}
+
+// reconcileKindAPIError records a failed call of the Kind Wrapper API in the KindAPIAvailable condition
+// The KindCluster is requeued once the circuit breaker lets the calls through again instead of failing the reconciliation
+func (r *KindClusterReconciler) reconcileKindAPIError(ctx context.Context, helper *patch.Helper, kindCluster *infrastructurev1alpha1.KindCluster, err error) (ctrl.Result, error) {
+	r.recordEvent(ctx, kindCluster, corev1.EventTypeWarning, KindAPIErrorEvent, "Kind Wrapper API call failed: %s", err)
+	if goerrors.Is(err, KindAPICircuitOpenError) {
+		conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindAPIAvailableCondition, infrastructurev1alpha1.KindAPICircuitOpenReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{RequeueAfter: kindApiCircuitBreakerCooldown}, r.patchKindCluster(ctx, helper, kindCluster)
+	}
+	conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindAPIAvailableCondition, infrastructurev1alpha1.KindAPIUnreachableReason, clusterv1.ConditionSeverityWarning, err.Error())
+	return ctrl.Result{}, kerrors.NewAggregate([]error{err, r.patchKindCluster(ctx, helper, kindCluster)})
+}
+
+// reconcileControlPlaneReachable sets the ControlPlaneReachable condition by opening a TCP connection to the control plane endpoint
+func (r *KindClusterReconciler) reconcileControlPlaneReachable(kindCluster *infrastructurev1alpha1.KindCluster) {
+	endpoint := kindCluster.Spec.ControlPlaneEndpoint
+	address := net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port))
+	conn, err := net.DialTimeout("tcp", address, controlPlaneDialTimeout)
+	if err != nil {
+		conditions.MarkFalse(kindCluster, infrastructurev1alpha1.ControlPlaneReachableCondition, infrastructurev1alpha1.ControlPlaneUnreachableReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return
+	}
+	_ = conn.Close()
+	conditions.MarkTrue(kindCluster, infrastructurev1alpha1.ControlPlaneReachableCondition)
+}
+
+// kindClientForHost returns a client of the Kind Wrapper API running on the KindHost with the specified name
+// The default client is returned in case the name is empty
+func (r *KindClusterReconciler) kindClientForHost(ctx context.Context, hostName string) (*KindClient, error) {
+	if hostName == "" {
+		return r.KindClient, nil
+	}
+	var host infrastructurev1alpha1.KindHost
+	if err := r.Get(ctx, types.NamespacedName{Name: hostName}, &host); err != nil {
+		return nil, err
+	}
+	return r.hostClients.forHost(ctx, &host)
+}
+
+// setKindClusterOwner records the KindCluster as the owner of its Kind cluster, an empty owner releases the Kind cluster
+// Kind clusters which do not exist anymore and Kind Wrapper API versions without ownership are ignored
+func setKindClusterOwner(ctx context.Context, kindClient *KindClient, clusterName string, owner KindClusterOwner) error {
+	if err := kindClient.SetClusterOwner(ctx, clusterName, owner); err != nil && err != KindClusterNotFoundError {
+		return err
+	}
+	return nil
+}
+
+// releaseKindCluster removes the owner of the Kind cluster kept by the deletion policy of a KindCluster
+// The TTL of the Kind cluster is cleared as well, so that the kept Kind cluster is not deleted by the Kind Wrapper API once it expires
+func releaseKindCluster(ctx context.Context, kindClient *KindClient, kindCluster *infrastructurev1alpha1.KindCluster) error {
+	if err := setKindClusterOwner(ctx, kindClient, kindClusterName(kindCluster), KindClusterOwner{}); err != nil {
+		return err
+	}
+	if kindCluster.Status.ExpiresAt == nil {
+		return nil
+	}
+	if err := kindClient.SetClusterTTL(ctx, kindClusterName(kindCluster), 0); err != nil && err != KindClusterNotFoundError {
+		return err
+	}
+	return nil
+}
+
+// reconcilePaused hands over the owner of the Kind cluster of a paused KindCluster once it has been created
+// clusterctl move pauses the owner Cluster before it removes the KindCluster, whose Kind cluster would be collected as an orphan
+// by the garbage collector of this management cluster until the moved KindCluster records its new UID as the owner
+func (r *KindClusterReconciler) reconcilePaused(ctx context.Context, kindCluster *infrastructurev1alpha1.KindCluster) (ctrl.Result, error) {
+	if kindCluster.IsBeingDeleted() || kindCluster.Status.OwnerHandedOver || kindCluster.Status.AppliedSpecHash == "" {
+		return ctrl.Result{}, nil
+	}
+	logger := log.FromContext(ctx)
+	clusterName := client.ObjectKeyFromObject(kindCluster).String()
+
+	helper, err := patch.NewHelper(kindCluster, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	kindClient, err := r.kindClientForHost(ctx, appliedHostName(kindCluster))
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to get Kind Wrapper API client of cluster %s", clusterName))
+		return ctrl.Result{}, err
+	}
+	if err := handOverKindCluster(ctx, kindClient, kindCluster); err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to hand over owner of Kind cluster of cluster %s", clusterName))
+		return ctrl.Result{}, err
+	}
+	logger.Info(fmt.Sprintf("Handed over owner of Kind cluster of paused cluster %s", clusterName))
+	return ctrl.Result{}, r.patchKindCluster(ctx, helper, kindCluster)
+}
+
+// handOverKindCluster records the owner of the Kind cluster without the identity of the management cluster
+// Garbage collectors only report Kind clusters recorded without a manager, the owner is recorded with the identity again
+// by the KindCluster once it is unpaused or by the moved KindCluster on the target management cluster
+func handOverKindCluster(ctx context.Context, kindClient *KindClient, kindCluster *infrastructurev1alpha1.KindCluster) error {
+	if err := setKindClusterOwner(ctx, kindClient, kindClusterName(kindCluster), kindClusterOwner(kindCluster, "")); err != nil {
+		return err
+	}
+	kindCluster.Status.OwnerHandedOver = true
+	return nil
+}
+
+// kindClusterOwner returns the owner of the Kind cluster of a KindCluster, its UID, the identity of its management cluster and labels
+// The labels of the KindCluster, e.g. its team, are extended by the labels identifying the KindCluster,
+// the name is left out in case it is too long for a label value
+func kindClusterOwner(kindCluster *infrastructurev1alpha1.KindCluster, managerID string) KindClusterOwner {
+	labels := map[string]string{}
+	for key, value := range kindCluster.Labels {
+		labels[key] = value
+	}
+	labels[infrastructurev1alpha1.KindClusterNamespaceLabel] = kindCluster.Namespace
+	if len(validation.IsValidLabelValue(kindCluster.Name)) == 0 {
+		labels[infrastructurev1alpha1.KindClusterNameLabel] = kindCluster.Name
+	}
+	labels[infrastructurev1alpha1.KindClusterUIDLabel] = string(kindCluster.UID)
+	return KindClusterOwner{UID: string(kindCluster.UID), Manager: managerID, Labels: labels}
+}
+
+// hostHasCapacity checks if the Kind cluster defined by the resolved spec fits into the capacity of its KindHost
+// Kind clusters already created on the host are counted with the nodes of their specs
+func (r *KindClusterReconciler) hostHasCapacity(ctx context.Context, kindCluster *infrastructurev1alpha1.KindCluster, spec infrastructurev1alpha1.KindClusterSpec) (bool, error) {
+	hostName := kindCluster.HostName()
+	if hostName == "" {
+		return true, nil
+	}
+	var host infrastructurev1alpha1.KindHost
+	if err := r.Get(ctx, types.NamespacedName{Name: hostName}, &host); err != nil {
+		return false, err
+	}
+
+	var kindClusters infrastructurev1alpha1.KindClusterList
+	if err := r.List(ctx, &kindClusters); err != nil {
+		return false, err
+	}
+	clusters, nodes := hostUsage(kindClusters.Items, hostName, kindCluster.UID)
+	return host.HasCapacityFor(clusters+1, nodes+spec.NodeCount()), nil
+}
+
+// reconcileScheduling selects a KindHost for the Kind cluster defined by the resolved spec and records the decision in the status
+// The default Kind Wrapper API is used in case no KindHost exists, false is returned in case no KindHost is suitable
+func (r *KindClusterReconciler) reconcileScheduling(ctx context.Context, kindCluster *infrastructurev1alpha1.KindCluster, spec infrastructurev1alpha1.KindClusterSpec) (bool, error) {
+	var hosts infrastructurev1alpha1.KindHostList
+	if err := r.List(ctx, &hosts, watchFilterListOptions(r.WatchFilterValue)...); err != nil {
+		return false, err
+	}
+	var kindClusters infrastructurev1alpha1.KindClusterList
+	if err := r.List(ctx, &kindClusters); err != nil {
+		return false, err
+	}
+
+	host, message, err := scheduleKindCluster(kindCluster, spec, hosts.Items, kindClusters.Items)
+	if err != nil {
+		return false, err
+	}
+	if host == nil && len(hosts.Items) > 0 {
+		recordScheduling(kindCluster, "", message)
+		conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindHostScheduledCondition, infrastructurev1alpha1.NoKindHostAvailableReason, clusterv1.ConditionSeverityWarning, message)
+		conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.NoKindHostAvailableReason, clusterv1.ConditionSeverityWarning, message)
+		return false, nil
+	}
+
+	hostName := ""
+	if host != nil {
+		hostName = host.Name
+	} else {
+		message = "no KindHost exists, the default Kind Wrapper API is used"
+	}
+	if recordScheduling(kindCluster, hostName, message) {
+		r.recordEvent(ctx, kindCluster, corev1.EventTypeNormal, KindClusterScheduledEvent, "%s", message)
+	}
+	conditions.MarkTrue(kindCluster, infrastructurev1alpha1.KindHostScheduledCondition)
+	return true, nil
+}
+
+// needsScheduling checks if a KindHost has to be selected for a Kind cluster without a host reference
+// Only Kind clusters which have never been provisioned or are being rescheduled are scheduled, Kind clusters
+// provisioned before the scheduler existed stay on the default Kind Wrapper API they were created on
+func needsScheduling(kindCluster *infrastructurev1alpha1.KindCluster) bool {
+	if kindCluster.Status.AppliedSpecHash != "" || kindCluster.Spec.HostRef != nil || hasHostAnnotation(kindCluster) {
+		return false
+	}
+	return kindCluster.Status.State == "" || kindCluster.Status.Scheduling != nil
+}
+
+// recordScheduling records the scheduling decision in the status of the KindCluster
+// The time of the decision is only updated when the decision changes, true is returned in that case
+func recordScheduling(kindCluster *infrastructurev1alpha1.KindCluster, hostName, message string) bool {
+	scheduling := kindCluster.Status.Scheduling
+	if scheduling == nil {
+		scheduling = &infrastructurev1alpha1.KindClusterSchedulingStatus{}
+		kindCluster.Status.Scheduling = scheduling
+	}
+	if scheduling.Host == hostName && scheduling.Message == message && !scheduling.LastScheduleTime.IsZero() {
+		return false
+	}
+	scheduling.Host = hostName
+	scheduling.Message = message
+	scheduling.LastScheduleTime = metav1.Now()
+	return true
+}
+
+// shouldReschedule checks if the Kind cluster failed on the KindHost selected by the scheduler and should be scheduled again
+func shouldReschedule(kindCluster *infrastructurev1alpha1.KindCluster) bool {
+	return kindCluster.Status.State == infrastructurev1alpha1.KindClusterStateFailed &&
+		kindCluster.Status.FailureReason != nil && *kindCluster.Status.FailureReason == capierrors.CreateClusterError &&
+		kindCluster.Spec.HostRef == nil && kindCluster.Status.Host != "" &&
+		kindCluster.Spec.Scheduling != nil && kindCluster.Spec.Scheduling.RescheduleOnFailure
+}
+
+// isPaused checks if the KindCluster has the paused annotation or its owner Cluster is paused
+func isPaused(ownerCluster *clusterv1.Cluster, kindCluster *infrastructurev1alpha1.KindCluster) bool {
+	if ownerCluster == nil {
+		return annotations.HasPaused(kindCluster)
+	}
+	return annotations.IsPaused(ownerCluster, kindCluster)
+}
+
+// reconcileAdoption adopts the existing Kind cluster named by the adopt annotation once it is running
+// The spec is reverse-engineered from the nodes of the Kind cluster and the kubeconfig Secret is created from its kubeconfig
+func (r *KindClusterReconciler) reconcileAdoption(ctx context.Context, helper *patch.Helper, kindCluster *infrastructurev1alpha1.KindCluster, ownerCluster *clusterv1.Cluster) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	clusterName := client.ObjectKeyFromObject(kindCluster).String()
+	name := kindClusterName(kindCluster)
+
+	hostName := appliedHostName(kindCluster)
+	kindClient, err := r.kindClientForHost(ctx, hostName)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to get Kind Wrapper API client of cluster %s", clusterName))
+		conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.KindHostUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, kerrors.NewAggregate([]error{err, r.patchKindCluster(ctx, helper, kindCluster)})
+	}
+
+	details, err := kindClient.GetClusterDetails(ctx, name)
+	if err == KindClusterNotFoundError {
+		logger.Info(fmt.Sprintf("Kind cluster %s to be adopted by cluster %s not found", name, clusterName))
+		conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.KindClusterNotFoundReason, clusterv1.ConditionSeverityWarning,
+			"Kind cluster %s to adopt does not exist", name)
+		return ctrl.Result{RequeueAfter: hostCapacityRequeue}, r.patchKindCluster(ctx, helper, kindCluster)
+	} else if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to look up Kind cluster %s to be adopted by cluster %s", name, clusterName))
+		return r.reconcileKindAPIError(ctx, helper, kindCluster, err)
+	}
+	conditions.MarkTrue(kindCluster, infrastructurev1alpha1.KindAPIAvailableCondition)
+
+	if !kindCluster.HasFinalizer(infrastructurev1alpha1.KindClusterFinalizerName) {
+		kindCluster.AddFinalizer(infrastructurev1alpha1.KindClusterFinalizerName)
+	}
+	if details.State != KindStateRunning {
+		kindCluster.Status.State = infrastructurev1alpha1.KindClusterStatePending
+		kindCluster.Status.Ready = false
+		conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.WaitingForKindClusterReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, r.patchKindCluster(ctx, helper, kindCluster)
+	}
+
+	if err := r.reconcileKubeConfigSecret(ctx, kindCluster, ownerCluster, details.KubeConfig); err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to create kubeconfig Secret of cluster %s", clusterName))
+		return ctrl.Result{}, err
+	}
+
+	if err := setKindClusterOwner(ctx, kindClient, name, kindClusterOwner(kindCluster, r.ManagerID)); err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to record owner of Kind cluster %s adopted by cluster %s", name, clusterName))
+		return r.reconcileKindAPIError(ctx, helper, kindCluster, err)
+	}
+
+	logger.Info(fmt.Sprintf("Adopting Kind cluster %s by cluster %s", name, clusterName))
+	r.recordEvent(ctx, kindCluster, corev1.EventTypeNormal, KindClusterAdoptedEvent, "Adopting running Kind cluster")
+	adoptKindClusterSpec(kindCluster, details)
+	spec, err := r.resolveRawConfig(ctx, kindCluster)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to resolve raw config of cluster %s", clusterName))
+		conditions.MarkFalse(kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition, infrastructurev1alpha1.WaitingForRawConfigReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, kerrors.NewAggregate([]error{err, r.patchKindCluster(ctx, helper, kindCluster)})
+	}
+	kindCluster.Status.AppliedSpecHash = spec.KindConfigHash()
+	kindCluster.Status.Host = hostName
+	kindCluster.Status.KindClusterName = name
+	setHostAnnotation(kindCluster, hostName)
+	kindCluster.Status.State = infrastructurev1alpha1.KindClusterStateRunning
+	kindCluster.Status.Ready = true
+	conditions.MarkTrue(kindCluster, infrastructurev1alpha1.KindClusterSpecAppliedCondition)
+	conditions.MarkTrue(kindCluster, infrastructurev1alpha1.KindClusterProvisionedCondition)
+	r.reconcileControlPlaneReachable(kindCluster)
+	return ctrl.Result{}, r.patchKindCluster(ctx, helper, kindCluster)
+}
+
+// reconcileKubeConfigSecret creates the Cluster API kubeconfig Secret of an adopted Kind cluster unless it already exists
+// The Secret is named after and owned by the owner Cluster, or by the KindCluster in case it has no owner yet
+func (r *KindClusterReconciler) reconcileKubeConfigSecret(ctx context.Context, kindCluster *infrastructurev1alpha1.KindCluster, ownerCluster *clusterv1.Cluster, kubeConfig string) error {
+	if kubeConfig == "" {
+		return nil
+	}
+	name := kindCluster.Name
+	owner := metav1.OwnerReference{
+		APIVersion: infrastructurev1alpha1.GroupVersion.String(),
+		Kind:       "KindCluster",
+		Name:       kindCluster.Name,
+		UID:        kindCluster.UID,
+	}
+	if ownerCluster != nil {
+		name = ownerCluster.Name
+		owner = metav1.OwnerReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+			Name:       ownerCluster.Name,
+			UID:        ownerCluster.UID,
+		}
+	}
+	secret := kubeconfig.GenerateSecretWithOwner(client.ObjectKey{Namespace: kindCluster.Namespace, Name: name}, []byte(kubeConfig), owner)
+	if err := r.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// adoptKindClusterSpec reverse-engineers the spec of an adopted Kind cluster from its nodes and control plane endpoint
+// The nodes are kept in case the KindCluster is configured by a raw Kind configuration
+func adoptKindClusterSpec(kindCluster *infrastructurev1alpha1.KindCluster, details KindClusterDetails) {
+	if !kindCluster.HasRawConfig() && len(details.Nodes) > 0 {
+		nodes := make([]infrastructurev1alpha1.KindClusterNode, 0, len(details.Nodes))
+		for _, node := range details.Nodes {
+			nodes = append(nodes, infrastructurev1alpha1.KindClusterNode{
+				Role:  infrastructurev1alpha1.KindClusterRole(node.Role),
+				Image: node.Image,
+			})
+		}
+		kindCluster.Spec.Nodes = nodes
+		if kindCluster.Spec.Networking.APIServerAddress == "" && kindCluster.Spec.Networking.APIServerPort == 0 {
+			kindCluster.Spec.Networking.APIServerAddress = details.Host
+			kindCluster.Spec.Networking.APIServerPort = details.Port
+		}
+	}
+	if !kindCluster.HasControlPlaneEndpoint() {
+		kindCluster.AddControlPlaneEndpoint(details.Host, details.Port)
+	}
+}
+
+// kindClusterName returns the name of the Kind cluster, the name recorded in the status takes precedence
+// Adopted Kind clusters keep their original name and Kind clusters provisioned before the name was recorded keep the legacy name
+func kindClusterName(kindCluster *infrastructurev1alpha1.KindCluster) string {
+	if kindCluster.Status.KindClusterName != "" {
+		return kindCluster.Status.KindClusterName
+	}
+	if name := kindCluster.Annotations[infrastructurev1alpha1.KindClusterAdoptAnnotation]; name != "" {
+		return name
+	}
+	if kindCluster.Status.AppliedSpecHash != "" || kindCluster.Status.State != "" {
+		return legacyClusterName(kindCluster.Namespace, kindCluster.Name)
+	}
+	return compositeClusterName(kindCluster.Namespace, kindCluster.Name)
+}
+
+// hasHostAnnotation checks if the KindCluster records the KindHost its Kind cluster was created on
+func hasHostAnnotation(kindCluster *infrastructurev1alpha1.KindCluster) bool {
+	_, ok := kindCluster.Annotations[infrastructurev1alpha1.KindClusterHostAnnotation]
+	return ok
+}
+
+// setHostAnnotation records the KindHost the Kind cluster was created on, the annotation is empty for the default Kind Wrapper API
+func setHostAnnotation(kindCluster *infrastructurev1alpha1.KindCluster, hostName string) {
+	if kindCluster.Annotations == nil {
+		kindCluster.Annotations = map[string]string{}
+	}
+	kindCluster.Annotations[infrastructurev1alpha1.KindClusterHostAnnotation] = hostName
+}
+
+// appliedHostName returns the name of the KindHost the Kind cluster was created on
+// Kind clusters without the status, e.g. moved by clusterctl, use the KindHost recorded by the annotation
+// Kind clusters which have not been created yet use the KindHost referenced by the spec or selected by the scheduler
+func appliedHostName(kindCluster *infrastructurev1alpha1.KindCluster) string {
+	if kindCluster.Status.AppliedSpecHash == "" {
+		if hostName, ok := kindCluster.Annotations[infrastructurev1alpha1.KindClusterHostAnnotation]; ok {
+			return hostName
+		}
+		if kindCluster.Spec.HostRef == nil && kindCluster.Status.Scheduling != nil {
+			return kindCluster.Status.Scheduling.Host
+		}
+		return kindCluster.HostName()
+	}
+	return kindCluster.Status.Host
+}
+
+// resolveRawConfig returns the spec of the KindCluster with the raw config read from the referenced ConfigMap key
+// The spec is returned unchanged in case it does not reference any ConfigMap
+func (r *KindClusterReconciler) resolveRawConfig(ctx context.Context, kindCluster *infrastructurev1alpha1.KindCluster) (infrastructurev1alpha1.KindClusterSpec, error) {
+	spec := kindCluster.Spec
+	if spec.RawConfigRef == nil {
+		return spec, nil
+	}
+
+	var configMap corev1.ConfigMap
+	key := types.NamespacedName{Namespace: kindCluster.Namespace, Name: spec.RawConfigRef.Name}
+	if err := r.Get(ctx, key, &configMap); err != nil {
+		return spec, err
+	}
+
+	rawConfig, ok := configMap.Data[spec.RawConfigRef.Key]
+	if !ok {
+		return spec, fmt.Errorf("key %s not found in ConfigMap %s", spec.RawConfigRef.Key, key)
+	}
+	spec.RawConfig = rawConfig
+	return spec, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
+// Paused KindClusters are filtered out and owner Clusters are watched, so that pausing and unpausing them triggers the reconciliation
+// KindClusters and Clusters without the watch filter label are filtered out in case the watch filter is set
 func (r *KindClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	r.hostClients = newKindHostClients(mgr.GetAPIReader(), r.CredentialsNamespace)
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("kindcluster-controller")
+	}
+	logger := mgr.GetLogger().WithName("kindcluster")
+	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&infrastructurev1alpha1.KindCluster{}).
-		Complete(r)
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(logger, r.WatchFilterValue)).
+		Build(r)
+	if err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &clusterv1.Cluster{}},
+		handler.EnqueueRequestsFromMapFunc(util.ClusterToInfrastructureMapFunc(infrastructurev1alpha1.GroupVersion.WithKind("KindCluster"))),
+		predicates.All(logger,
+			predicates.Any(logger, predicates.ClusterUnpaused(logger), clusterUpdatePaused()),
+			predicates.ResourceHasFilterLabel(logger, r.WatchFilterValue),
+		),
+	)
+}
+
+// clusterUpdatePaused returns a predicate passing the update events of Clusters getting paused, e.g. by clusterctl move,
+// so that the owners of the Kind clusters are handed over before the KindClusters are moved
+func clusterUpdatePaused() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, ok := e.ObjectOld.(*clusterv1.Cluster)
+			if !ok {
+				return false
+			}
+			newCluster, ok := e.ObjectNew.(*clusterv1.Cluster)
+			if !ok {
+				return false
+			}
+			return !oldCluster.Spec.Paused && newCluster.Spec.Paused
+		},
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
+// watchFilterListOptions returns the options listing only the objects with the watch filter label of the specified value
+// No options are returned in case the watch filter is not set
+func watchFilterListOptions(watchFilterValue string) []client.ListOption {
+	if watchFilterValue == "" {
+		return nil
+	}
+	return []client.ListOption{client.MatchingLabels{clusterv1.WatchLabel: watchFilterValue}}
 }
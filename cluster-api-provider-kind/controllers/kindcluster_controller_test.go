@@ -5,8 +5,19 @@ import (
 	"context"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"net/http"
+	"net/http/httptest"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -88,6 +99,684 @@ var _ = Describe("KindCluster Controller", func() {
 		//Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
 		Expect(fetched.HasFinalizer(v1alpha1.KindClusterFinalizerName)).To(BeTrue())
 		Expect(fetched.HasControlPlaneEndpoint()).To(BeTrue())
+		Expect(fetched.Status.ObservedGeneration).To(Equal(fetched.Generation))
+		Expect(conditions.IsTrue(fetched, v1alpha1.KindClusterProvisionedCondition)).To(BeTrue())
+		Expect(conditions.IsTrue(fetched, clusterv1.ReadyCondition)).To(BeTrue())
+		Expect(conditions.Has(fetched, v1alpha1.ControlPlaneReachableCondition)).To(BeTrue())
+	})
+
+	It("should record a shortened name of the Kind cluster of a long KindCluster name", func() {
+		nameMockKindApiServer := &MockKindApiServer{}
+		nameMockKindApiServer.Init()
+		defer nameMockKindApiServer.server.Close()
+		nameMockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		nameMockKindApiServer.statusResponses = []MockKindApiServerResponse{NotFoundMockApiResponse}
+
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-naming"},
+			Spec:       v1alpha1.KindHostSpec{URL: nameMockKindApiServer.server.URL},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(context.Background(), host))).Should(Succeed())
+		}()
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-with-a-name-too-long-for-the-node-container-hostnames",
+			Namespace: "default",
+		}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: v1alpha1.KindClusterSpec{
+				Nodes:   []v1alpha1.KindClusterNode{{Role: "control-plane"}},
+				HostRef: &v1alpha1.KindHostReference{Name: host.Name},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+			g.Expect(fetched.Status.KindClusterName).To(Equal(compositeClusterName(key.Namespace, key.Name)))
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(len(fetched.Status.KindClusterName)).To(BeNumerically("<=", kindClusterNameMaxLength))
+
+		Expect(nameMockKindApiServer.CreatePayloads()).To(HaveLen(1))
+		config, err := v1alpha1.ParseKindConfig(nameMockKindApiServer.CreatePayloads()[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Name).To(Equal(fetched.Status.KindClusterName))
+
+		Expect(k8sClient.Delete(context.Background(), fetched)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(nameMockKindApiServer.DeletePaths()).To(ContainElement(HaveSuffix("/" + fetched.Status.KindClusterName)))
+		}, 20*time.Second, time.Second).Should(Succeed())
+	})
+
+	It("should keep the legacy name of a Kind cluster provisioned before the name was recorded", func() {
+		mockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-provisioned-before-the-kind-cluster-name-was-recorded",
+			Namespace: "default",
+		}
+		legacyName := legacyClusterName(key.Namespace, key.Name)
+		Expect(compositeClusterName(key.Namespace, key.Name)).NotTo(Equal(legacyName))
+
+		// Simulate a KindCluster provisioned by a previous version, which recorded neither the name nor the spec hash
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   key.Namespace,
+				Annotations: map[string]string{clusterv1.PausedAnnotation: ""},
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+		kindCluster.Status.State = v1alpha1.KindClusterStateRunning
+		kindCluster.Status.Ready = true
+		Expect(k8sClient.Status().Update(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			delete(fetched.Annotations, clusterv1.PausedAnnotation)
+			g.Expect(k8sClient.Update(context.Background(), fetched)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.KindClusterName).To(Equal(legacyName))
+			g.Expect(fetched.Status.AppliedSpecHash).NotTo(BeEmpty())
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+		Expect(mockKindApiServer.CreatePayloads()).To(BeEmpty())
+
+		Expect(k8sClient.Delete(context.Background(), fetched)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(mockKindApiServer.DeletePaths()).To(ContainElement(HaveSuffix("/" + legacyName)))
+		}, 20*time.Second, time.Second).Should(Succeed())
+		mockKindApiServer.SetDefaultStatusResponse(NotFoundMockApiResponse)
+		Eventually(func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(context.Background(), key, fetched))
+		}, 20*time.Second, time.Second).Should(BeTrue())
+	})
+
+	It("should set failure reason and conditions when the Kind cluster fails", func() {
+		mockKindApiServer.SetDefaultStatusResponse(FailedStatusMockApiResponse)
+		mockKindApiServer.AddStatusResponse(NotFoundMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster5",
+			Namespace: "default",
+		}
+
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateFailed))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		Expect(fetched.Status.FailureReason).To(PointTo(Equal(capierrors.CreateClusterError)))
+		Expect(fetched.Status.FailureMessage).To(PointTo(Equal("node image not found")))
+		Expect(conditions.GetReason(fetched, v1alpha1.KindClusterProvisionedCondition)).To(Equal(v1alpha1.KindClusterFailedReason))
+		Expect(conditions.GetSeverity(fetched, clusterv1.ReadyCondition)).To(PointTo(Equal(clusterv1.ConditionSeverityError)))
+	})
+
+	It("should fail the KindCluster when the Kind cluster is not running within the provisioning timeout", func() {
+		timeoutMockKindApiServer := &MockKindApiServer{}
+		timeoutMockKindApiServer.Init()
+		defer timeoutMockKindApiServer.server.Close()
+		timeoutMockKindApiServer.SetDefaultStatusResponse(PendingStatusMockApiResponse)
+		timeoutMockKindApiServer.statusResponses = []MockKindApiServerResponse{NotFoundMockApiResponse}
+
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-timeout"},
+			Spec:       v1alpha1.KindHostSpec{URL: timeoutMockKindApiServer.server.URL},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(context.Background(), host))).Should(Succeed())
+		}()
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-timeout",
+			Namespace: "default",
+		}
+		spec.HostRef = &v1alpha1.KindHostReference{Name: host.Name}
+		spec.Provisioning = &v1alpha1.KindClusterProvisioning{Timeout: &metav1.Duration{Duration: 2 * time.Second}}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateFailed))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(conditions.GetReason(fetched, v1alpha1.KindClusterProvisionedCondition)).To(Equal(v1alpha1.ProvisioningTimeoutReason))
+		Expect(fetched.Status.FailureMessage).To(PointTo(ContainSubstring("not running after 2s")))
+		Expect(fetched.Status.Provisioning.Attempts).To(Equal(int32(1)))
+		Expect(timeoutMockKindApiServer.CreatePayloads()).To(HaveLen(1))
+	})
+
+	It("should retry failed attempts and start over a failed KindCluster with the retry annotation", func() {
+		var mutex sync.Mutex
+		creates, created := 0, false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			switch r.Method {
+			case http.MethodPost:
+				creates++
+				created = true
+			case http.MethodDelete:
+				created = false
+			default:
+				if !created {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(FailedStatusMockApiResponse.Payload))
+			}
+		}))
+		defer server.Close()
+		createCount := func() int {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return creates
+		}
+
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-retry"},
+			Spec:       v1alpha1.KindHostSpec{URL: server.URL},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(context.Background(), host))).Should(Succeed())
+		}()
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-retry",
+			Namespace: "default",
+		}
+		maxRetries := int32(1)
+		spec.HostRef = &v1alpha1.KindHostReference{Name: host.Name}
+		spec.Provisioning = &v1alpha1.KindClusterProvisioning{MaxRetries: &maxRetries}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(conditions.GetReason(fetched, v1alpha1.KindClusterProvisionedCondition)).To(Equal(v1alpha1.WaitingForRetryReason))
+			g.Expect(fetched.Status.Provisioning).NotTo(BeNil())
+			g.Expect(fetched.Status.Provisioning.NextAttemptTime).NotTo(BeNil())
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStatePending))
+		Expect(fetched.Status.Provisioning.LastFailure).To(Equal("node image not found"))
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateFailed))
+		}, 40*time.Second, time.Second).Should(Succeed())
+		Expect(conditions.GetReason(fetched, v1alpha1.KindClusterProvisionedCondition)).To(Equal(v1alpha1.ProvisioningRetriesExceededReason))
+		Expect(fetched.Status.FailureMessage).To(PointTo(ContainSubstring("giving up after 2 attempts")))
+		Expect(fetched.Status.Provisioning.Attempts).To(Equal(int32(2)))
+		Expect(createCount()).To(BeNumerically(">=", 2))
+		failedCreates := createCount()
+
+		By("requesting a retry of the failed KindCluster")
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			fetched.Annotations = map[string]string{v1alpha1.KindClusterRetryAnnotation: ""}
+			g.Expect(k8sClient.Update(context.Background(), fetched)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Annotations).NotTo(HaveKey(v1alpha1.KindClusterRetryAnnotation))
+			g.Expect(createCount()).To(BeNumerically(">", failedCreates))
+		}, 20*time.Second, time.Second).Should(Succeed())
+	})
+
+	It("should fail the KindCluster when the Kind Wrapper API rejects its config", func() {
+		mockKindApiServer.SetDefaultCreateResponse(BadRequestMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster6",
+			Namespace: "default",
+		}
+
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateFailed))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		Expect(fetched.Status.FailureReason).To(PointTo(Equal(capierrors.InvalidConfigurationClusterError)))
+		Expect(*fetched.Status.FailureMessage).To(ContainSubstring("Failed to parse request payload"))
+		Expect(conditions.GetReason(fetched, v1alpha1.KindClusterProvisionedCondition)).To(Equal(v1alpha1.KindClusterInvalidConfigReason))
+	})
+
+	It("should create Kind Cluster from a raw config referenced in a ConfigMap", func() {
+		mockKindApiServer.SetDefaultStatusResponse(PendingStatusMockApiResponse)
+		mockKindApiServer.AddStatusResponse(NotFoundMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster4",
+			Namespace: "default",
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kind-config",
+				Namespace: key.Namespace,
+			},
+			Data: map[string]string{
+				"config.yaml": "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n- role: control-plane\n  labels:\n    tier: raw\n",
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), configMap)).Should(Succeed())
+
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: v1alpha1.KindClusterSpec{
+				RawConfigRef: &v1alpha1.KindClusterConfigMapKeyReference{Name: "kind-config", Key: "config.yaml"},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			var payloads []string
+			for _, payload := range mockKindApiServer.CreatePayloads() {
+				if strings.Contains(payload, "name: default-kind-cluster4") {
+					payloads = append(payloads, payload)
+				}
+			}
+			g.Expect(payloads).NotTo(BeEmpty())
+			g.Expect(payloads[0]).To(ContainSubstring("tier: raw"))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+	})
+
+	It("should recreate the Kind cluster when the spec changes with the Recreate strategy", func() {
+		mockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster7",
+			Namespace: "default",
+		}
+
+		spec.UpdateStrategy = v1alpha1.KindClusterUpdateStrategyRecreate
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+			g.Expect(fetched.Status.AppliedSpecHash).To(Equal(spec.KindConfigHash()))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			fetched.Spec.Nodes = append(fetched.Spec.Nodes, v1alpha1.KindClusterNode{Role: v1alpha1.KindClusterRoleWorker})
+			g.Expect(k8sClient.Update(context.Background(), fetched)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+		newHash := fetched.Spec.KindConfigHash()
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRecreating))
+			g.Expect(conditions.GetReason(fetched, v1alpha1.KindClusterSpecAppliedCondition)).To(Equal(v1alpha1.KindClusterRecreatingReason))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		mockKindApiServer.SetDefaultStatusResponse(NotFoundMockApiResponse)
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.AppliedSpecHash).To(Equal(newHash))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		var payloads []string
+		for _, payload := range mockKindApiServer.CreatePayloads() {
+			if strings.Contains(payload, "name: default-kind-cluster7") {
+				payloads = append(payloads, payload)
+			}
+		}
+		Expect(payloads).NotTo(BeEmpty())
+		Expect(payloads[0]).To(ContainSubstring("role: worker"))
+
+		mockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+			g.Expect(conditions.IsTrue(fetched, v1alpha1.KindClusterSpecAppliedCondition)).To(BeTrue())
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+	})
+
+	It("should report spec drift without recreating the Kind cluster with the Immutable strategy", func() {
+		mockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster8",
+			Namespace: "default",
+		}
+
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(conditions.IsTrue(fetched, v1alpha1.KindClusterSpecAppliedCondition)).To(BeTrue())
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			fetched.Spec.Networking.KubeProxyMode = v1alpha1.KindClusterKubeProxyModeIPVS
+			g.Expect(k8sClient.Update(context.Background(), fetched)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(conditions.GetReason(fetched, v1alpha1.KindClusterSpecAppliedCondition)).To(Equal(v1alpha1.KindClusterSpecDriftedReason))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+		Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+	})
+
+	It("should create the Kind cluster on the referenced KindHost", func() {
+		hostMockKindApiServer := &MockKindApiServer{}
+		hostMockKindApiServer.Init()
+		defer hostMockKindApiServer.server.Close()
+		hostMockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		hostMockKindApiServer.statusResponses = []MockKindApiServerResponse{NotFoundMockApiResponse}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-01-credentials", Namespace: "default"},
+			Data:       map[string][]byte{v1alpha1.KindHostCredentialsTokenKey: []byte("secret")},
+		}
+		Expect(k8sClient.Create(context.Background(), secret)).Should(Succeed())
+
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-01"},
+			Spec: v1alpha1.KindHostSpec{
+				URL:                  hostMockKindApiServer.server.URL,
+				CredentialsSecretRef: &v1alpha1.KindHostSecretReference{Name: secret.Name},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), host)).Should(Succeed())
+		}()
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster9",
+			Namespace: "default",
+		}
+
+		spec.HostRef = &v1alpha1.KindHostReference{Name: host.Name}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		Expect(fetched.Status.Host).To(Equal(host.Name))
+		Expect(hostMockKindApiServer.CreatePayloads()).To(HaveLen(1))
+		Expect(hostMockKindApiServer.CreatePayloads()[0]).To(ContainSubstring("name: default-kind-cluster9"))
+	})
+
+	It("should wait for capacity of the referenced KindHost", func() {
+		maxClusters := int32(0)
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-02"},
+			Spec: v1alpha1.KindHostSpec{
+				URL:      mockKindApiServer.server.URL,
+				Capacity: v1alpha1.KindHostCapacity{MaxClusters: &maxClusters},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), host)).Should(Succeed())
+		}()
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster10",
+			Namespace: "default",
+		}
+
+		spec.HostRef = &v1alpha1.KindHostReference{Name: host.Name}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(conditions.GetReason(fetched, v1alpha1.KindClusterProvisionedCondition)).To(Equal(v1alpha1.WaitingForHostCapacityReason))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+		Expect(fetched.Status.AppliedSpecHash).To(BeEmpty())
+	})
+
+	It("should report an unavailable KindHost", func() {
+		key := types.NamespacedName{
+			Name:      "kind-cluster11",
+			Namespace: "default",
+		}
+
+		spec.HostRef = &v1alpha1.KindHostReference{Name: "missing"}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(conditions.GetReason(fetched, v1alpha1.KindClusterProvisionedCondition)).To(Equal(v1alpha1.KindHostUnavailableReason))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+	})
+
+	It("should report the open circuit breaker of a failing Kind Wrapper API", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-failing"},
+			Spec:       v1alpha1.KindHostSpec{URL: server.URL},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), host)).Should(Succeed())
+		}()
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-failing-api",
+			Namespace: "default",
+		}
+		spec.HostRef = &v1alpha1.KindHostReference{Name: host.Name}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(conditions.IsFalse(fetched, v1alpha1.KindAPIAvailableCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(fetched, v1alpha1.KindAPIAvailableCondition)).To(Equal(v1alpha1.KindAPICircuitOpenReason))
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(fetched.Status.State).To(BeEmpty())
+	})
+
+	It("should not reconcile a KindCluster until its owner Cluster is unpaused", func() {
+		key := types.NamespacedName{
+			Name:      "kind-cluster-paused",
+			Namespace: "default",
+		}
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "paused-cluster", Namespace: key.Namespace},
+			Spec: clusterv1.ClusterSpec{
+				Paused: true,
+				InfrastructureRef: &corev1.ObjectReference{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       "KindCluster",
+					Name:       key.Name,
+					Namespace:  key.Namespace,
+				},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), cluster)).Should(Succeed())
+
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+					UID:        cluster.UID,
+				}},
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Consistently(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.HasFinalizer(v1alpha1.KindClusterFinalizerName)).To(BeFalse())
+			g.Expect(fetched.Status.State).To(BeEmpty())
+		}, 5*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster)).To(Succeed())
+			cluster.Spec.Paused = false
+			g.Expect(k8sClient.Update(context.Background(), cluster)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.HasFinalizer(v1alpha1.KindClusterFinalizerName)).To(BeTrue())
+			g.Expect(fetched.Status.State).NotTo(BeEmpty())
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+	})
+
+	It("should not reconcile a KindCluster with the paused annotation", func() {
+		key := types.NamespacedName{
+			Name:      "kind-cluster-annotated",
+			Namespace: "default",
+		}
+
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   key.Namespace,
+				Annotations: map[string]string{clusterv1.PausedAnnotation: ""},
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Consistently(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(BeEmpty())
+		}, 5*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			delete(fetched.Annotations, clusterv1.PausedAnnotation)
+			g.Expect(k8sClient.Update(context.Background(), fetched)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).NotTo(BeEmpty())
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		// Stop polling the shared mock, so that the following tests get their status responses
+		Expect(k8sClient.Delete(context.Background(), fetched)).Should(Succeed())
+		Eventually(func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(context.Background(), key, fetched))
+		}, 20*time.Second, time.Second).Should(BeTrue())
 	})
 
 	It("should delete all related resources when KindCluster CR is deleted", func() {
@@ -118,4 +807,474 @@ var _ = Describe("KindCluster Controller", func() {
 
 	})
 
+	It("should keep the Kind cluster with the Orphan deletion policy and adopt it again", func() {
+		mockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		mockKindApiServer.AddStatusResponse(NotFoundMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-orphan",
+			Namespace: "default",
+		}
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphan-cluster", Namespace: key.Namespace},
+		}
+		Expect(k8sClient.Create(context.Background(), cluster)).Should(Succeed())
+
+		spec.DeletionPolicy = v1alpha1.KindClusterDeletionPolicyOrphan
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+					UID:        cluster.UID,
+				}},
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.Ready).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(k8sClient.Delete(context.Background(), fetched)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(apierrors.IsNotFound(k8sClient.Get(context.Background(), key, fetched))).To(BeTrue())
+			g.Expect(apierrors.IsNotFound(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cluster), cluster))).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(mockKindApiServer.DeletePaths()).NotTo(ContainElement(HaveSuffix("/default-kind-cluster-orphan")))
+
+		By("adopting the orphaned Kind cluster")
+		adopting := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   key.Namespace,
+				Annotations: map[string]string{v1alpha1.KindClusterHostAnnotation: ""},
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), adopting)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.Ready).To(BeTrue())
+			g.Expect(fetched.Status.AppliedSpecHash).NotTo(BeEmpty())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		created := 0
+		for _, payload := range mockKindApiServer.CreatePayloads() {
+			if strings.Contains(payload, "default-kind-cluster-orphan") {
+				created++
+			}
+		}
+		Expect(created).To(Equal(1))
+
+		fetched.Spec.DeletionPolicy = v1alpha1.KindClusterDeletionPolicyDelete
+		Expect(k8sClient.Update(context.Background(), fetched)).Should(Succeed())
+		Expect(k8sClient.Delete(context.Background(), fetched)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(mockKindApiServer.DeletePaths()).To(ContainElement(HaveSuffix("/default-kind-cluster-orphan")))
+		}, 20*time.Second, time.Second).Should(Succeed())
+	})
+
+	It("should keep the Kind cluster and the owner Cluster with the Retain deletion policy", func() {
+		mockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		mockKindApiServer.AddStatusResponse(NotFoundMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-retain",
+			Namespace: "default",
+		}
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "retain-cluster", Namespace: key.Namespace},
+		}
+		Expect(k8sClient.Create(context.Background(), cluster)).Should(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), cluster)).Should(Succeed())
+		}()
+
+		spec.DeletionPolicy = v1alpha1.KindClusterDeletionPolicyRetain
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+					UID:        cluster.UID,
+				}},
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.Ready).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Expect(k8sClient.Delete(context.Background(), fetched)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(apierrors.IsNotFound(k8sClient.Get(context.Background(), key, fetched))).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Consistently(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			g.Expect(cluster.DeletionTimestamp).To(BeNil())
+		}, 3*time.Second, time.Second).Should(Succeed())
+		Expect(mockKindApiServer.DeletePaths()).NotTo(ContainElement(HaveSuffix("/default-kind-cluster-retain")))
+	})
+
+	It("should delete the KindCluster and the owner Cluster once the TTL expires", func() {
+		mockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		mockKindApiServer.AddStatusResponse(NotFoundMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-ttl",
+			Namespace: "default",
+		}
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "ttl-cluster", Namespace: key.Namespace},
+		}
+		Expect(k8sClient.Create(context.Background(), cluster)).Should(Succeed())
+
+		spec.TTL = &metav1.Duration{Duration: time.Hour}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   key.Namespace,
+				Annotations: map[string]string{v1alpha1.KindClusterExtendTTLAnnotation: "30m"},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+					UID:        cluster.UID,
+				}},
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.Ready).To(BeTrue())
+			g.Expect(fetched.Status.ExpiresAt).NotTo(BeNil())
+			g.Expect(fetched.Status.ExpiresAt.Time).To(BeTemporally("==", fetched.CreationTimestamp.Add(90*time.Minute)))
+			g.Expect(fetched.Annotations).To(HaveKeyWithValue(v1alpha1.KindClusterTTLStartAnnotation, fetched.CreationTimestamp.UTC().Format(time.RFC3339)))
+			g.Expect(fetched.Status.RemainingTTL).NotTo(BeEmpty())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		By("shortening the TTL")
+		fetched.Spec.TTL = &metav1.Duration{Duration: time.Second}
+		delete(fetched.Annotations, v1alpha1.KindClusterExtendTTLAnnotation)
+		Expect(k8sClient.Update(context.Background(), fetched)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(mockKindApiServer.DeletePaths()).To(ContainElement(HaveSuffix("/default-kind-cluster-ttl")))
+		}, 20*time.Second, time.Second).Should(Succeed())
+		mockKindApiServer.SetDefaultStatusResponse(NotFoundMockApiResponse)
+		Eventually(func(g Gomega) {
+			g.Expect(apierrors.IsNotFound(k8sClient.Get(context.Background(), key, fetched))).To(BeTrue())
+			g.Expect(apierrors.IsNotFound(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cluster), cluster))).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+	})
+
+	It("should keep the Kind cluster and the owner Cluster with the Retain deletion policy once the TTL expires", func() {
+		mockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		mockKindApiServer.AddStatusResponse(NotFoundMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-ttl-retain",
+			Namespace: "default",
+		}
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "ttl-retain-cluster", Namespace: key.Namespace},
+		}
+		Expect(k8sClient.Create(context.Background(), cluster)).Should(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), cluster)).Should(Succeed())
+		}()
+
+		spec.DeletionPolicy = v1alpha1.KindClusterDeletionPolicyRetain
+		spec.TTL = &metav1.Duration{Duration: 3 * time.Second}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+					UID:        cluster.UID,
+				}},
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(apierrors.IsNotFound(k8sClient.Get(context.Background(), key, fetched))).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Consistently(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			g.Expect(cluster.DeletionTimestamp).To(BeNil())
+		}, 3*time.Second, time.Second).Should(Succeed())
+		Expect(mockKindApiServer.DeletePaths()).NotTo(ContainElement(HaveSuffix("/default-kind-cluster-ttl-retain")))
+	})
+
+	It("should adopt an existing Kind cluster by its name", func() {
+		mockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-adopted",
+			Namespace: "default",
+		}
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "adopted-cluster", Namespace: key.Namespace},
+		}
+		Expect(k8sClient.Create(context.Background(), cluster)).Should(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), cluster)).Should(Succeed())
+		}()
+
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   key.Namespace,
+				Annotations: map[string]string{v1alpha1.KindClusterAdoptAnnotation: "hand-made"},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+					UID:        cluster.UID,
+				}},
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(conditions.GetReason(fetched, v1alpha1.KindClusterProvisionedCondition)).To(Equal(v1alpha1.KindClusterNotFoundReason))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		mockKindApiServer.SetDetailsResponse(DetailsMockApiResponse)
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.Ready).To(BeTrue())
+			g.Expect(fetched.Status.AppliedSpecHash).NotTo(BeEmpty())
+		}, 40*time.Second, time.Second).Should(Succeed())
+
+		Expect(fetched.Spec.Nodes).To(Equal([]v1alpha1.KindClusterNode{
+			{Role: v1alpha1.KindClusterRoleControlPlane, Image: "kindest/node:v1.26.3"},
+			{Role: v1alpha1.KindClusterRoleWorker, Image: "kindest/node:v1.26.3"},
+		}))
+		Expect(fetched.Spec.ControlPlaneEndpoint).To(Equal(v1alpha1.KindClusterControlPlaneEndpoint{Host: "127.0.0.1", Port: 6443}))
+		Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+		Expect(fetched.HasFinalizer(v1alpha1.KindClusterFinalizerName)).To(BeTrue())
+		Expect(fetched.Spec.KindConfigHash()).To(Equal(fetched.Status.AppliedSpecHash))
+
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: "adopted-cluster-kubeconfig", Namespace: key.Namespace}, secret)).To(Succeed())
+		Expect(string(secret.Data["value"])).To(ContainSubstring("kind: Config"))
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		Expect(secret.OwnerReferences[0].Name).To(Equal(cluster.Name))
+
+		for _, payload := range mockKindApiServer.CreatePayloads() {
+			Expect(payload).NotTo(ContainSubstring("hand-made"))
+		}
+
+		fetched.Spec.DeletionPolicy = v1alpha1.KindClusterDeletionPolicyRetain
+		Expect(k8sClient.Update(context.Background(), fetched)).Should(Succeed())
+		Expect(k8sClient.Delete(context.Background(), fetched)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(apierrors.IsNotFound(k8sClient.Get(context.Background(), key, fetched))).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(mockKindApiServer.DeletePaths()).NotTo(ContainElement(HaveSuffix("/hand-made")))
+	})
+
+	It("should recreate a lost Kind cluster with the Recreate recovery policy", func() {
+		lostMockKindApiServer := &MockKindApiServer{}
+		lostMockKindApiServer.Init()
+		defer lostMockKindApiServer.server.Close()
+		lostMockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		lostMockKindApiServer.statusResponses = []MockKindApiServerResponse{NotFoundMockApiResponse}
+
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-lost-recreate"},
+			Spec:       v1alpha1.KindHostSpec{URL: lostMockKindApiServer.server.URL},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(context.Background(), host))).Should(Succeed())
+		}()
+
+		key := types.NamespacedName{Name: "lost-cluster-recreated", Namespace: "default"}
+		spec.HostRef = &v1alpha1.KindHostReference{Name: host.Name}
+		spec.RecoveryPolicy = v1alpha1.KindClusterRecoveryPolicyRecreate
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec:       spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(lostMockKindApiServer.CreatePayloads()).To(HaveLen(1))
+
+		// The Kind cluster disappears, the metadata update triggers the check without waiting for the check interval
+		lostMockKindApiServer.SetDefaultStatusResponse(NotFoundMockApiResponse)
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			fetched.Annotations = map[string]string{"test": "lost"}
+			g.Expect(k8sClient.Update(context.Background(), fetched)).To(Succeed())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(len(lostMockKindApiServer.CreatePayloads())).To(BeNumerically(">=", 2))
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+		Expect(fetched.Status.State).NotTo(Equal(v1alpha1.KindClusterStateFailed))
+		Expect(conditions.IsTrue(fetched, v1alpha1.KindClusterLostCondition)).To(BeTrue())
+
+		lostMockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+			g.Expect(conditions.IsTrue(fetched, v1alpha1.KindClusterProvisionedCondition)).To(BeTrue())
+			g.Expect(conditions.IsFalse(fetched, v1alpha1.KindClusterLostCondition)).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(conditions.GetReason(fetched, v1alpha1.KindClusterLostCondition)).To(Equal(v1alpha1.KindClusterRecoveredReason))
+
+		lostMockKindApiServer.SetDefaultStatusResponse(NotFoundMockApiResponse)
+		Expect(k8sClient.Delete(context.Background(), fetched)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(apierrors.IsNotFound(k8sClient.Get(context.Background(), key, fetched))).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+	})
+
+	It("should fail a lost Kind cluster with the Fail recovery policy", func() {
+		lostMockKindApiServer := &MockKindApiServer{}
+		lostMockKindApiServer.Init()
+		defer lostMockKindApiServer.server.Close()
+		lostMockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		lostMockKindApiServer.statusResponses = []MockKindApiServerResponse{NotFoundMockApiResponse}
+
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-lost-fail"},
+			Spec:       v1alpha1.KindHostSpec{URL: lostMockKindApiServer.server.URL},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(context.Background(), host))).Should(Succeed())
+		}()
+
+		key := types.NamespacedName{Name: "lost-cluster-failed", Namespace: "default"}
+		spec.HostRef = &v1alpha1.KindHostReference{Name: host.Name}
+		spec.RecoveryPolicy = v1alpha1.KindClusterRecoveryPolicyFail
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec:       spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		lostMockKindApiServer.SetDefaultStatusResponse(NotFoundMockApiResponse)
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			fetched.Annotations = map[string]string{"test": "lost"}
+			g.Expect(k8sClient.Update(context.Background(), fetched)).To(Succeed())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateFailed))
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(fetched.Status.FailureReason).To(PointTo(Equal(capierrors.UpdateClusterError)))
+		Expect(conditions.GetReason(fetched, v1alpha1.KindClusterProvisionedCondition)).To(Equal(v1alpha1.KindClusterLostReason))
+		Expect(conditions.IsTrue(fetched, v1alpha1.KindClusterLostCondition)).To(BeTrue())
+		Expect(lostMockKindApiServer.CreatePayloads()).To(HaveLen(1))
+
+		Expect(k8sClient.Delete(context.Background(), fetched)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(apierrors.IsNotFound(k8sClient.Get(context.Background(), key, fetched))).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+	})
+
+	It("should record lifecycle events with the Kind cluster name and the Kind Wrapper API host", func() {
+		eventsMockKindApiServer := &MockKindApiServer{}
+		eventsMockKindApiServer.Init()
+		defer eventsMockKindApiServer.server.Close()
+		eventsMockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		eventsMockKindApiServer.statusResponses = []MockKindApiServerResponse{NotFoundMockApiResponse}
+
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-events"},
+			Spec:       v1alpha1.KindHostSpec{URL: eventsMockKindApiServer.server.URL},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(context.Background(), host))).Should(Succeed())
+		}()
+
+		key := types.NamespacedName{Name: "kind-cluster-events", Namespace: "default"}
+		spec.HostRef = &v1alpha1.KindHostReference{Name: host.Name}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec:       spec,
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		eventsMockKindApiServer.SetDefaultStatusResponse(NotFoundMockApiResponse)
+		Expect(k8sClient.Delete(context.Background(), fetched)).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(apierrors.IsNotFound(k8sClient.Get(context.Background(), key, fetched))).To(BeTrue())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			events := &corev1.EventList{}
+			g.Expect(k8sClient.List(context.Background(), events, client.InNamespace(key.Namespace))).To(Succeed())
+			reasons := map[string]corev1.Event{}
+			for _, event := range events.Items {
+				if event.InvolvedObject.Kind == "KindCluster" && event.InvolvedObject.Name == key.Name {
+					reasons[event.Reason] = event
+				}
+			}
+			for _, reason := range []string{KindClusterCreationRequestedEvent, KindClusterReadyEvent, KindClusterDeletingEvent, KindClusterDeletedEvent} {
+				g.Expect(reasons).To(HaveKey(reason))
+				event := reasons[reason]
+				g.Expect(event.Type).To(Equal(corev1.EventTypeNormal))
+				g.Expect(event.Annotations).To(HaveKeyWithValue(KindClusterNameEventAnnotation, fetched.Status.KindClusterName))
+				g.Expect(event.Annotations).To(HaveKeyWithValue(KindAPIHostEventAnnotation, eventsMockKindApiServer.server.URL))
+				g.Expect(event.Message).To(ContainSubstring(fetched.Status.KindClusterName))
+				g.Expect(event.Message).To(ContainSubstring(eventsMockKindApiServer.server.URL))
+			}
+		}, 20*time.Second, time.Second).Should(Succeed())
+	})
 })
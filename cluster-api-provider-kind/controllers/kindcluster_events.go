@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	infrastructurev1alpha1 "cluster-api-provider-kind/api/v1alpha1"
+)
+
+// Reasons of the events recorded for KindClusters
+const (
+	KindClusterScheduledEvent           = "Scheduled"
+	KindClusterCreationRequestedEvent   = "CreationRequested"
+	KindClusterCreationFailedEvent      = "CreationFailed"
+	KindClusterReadyEvent               = "Ready"
+	KindClusterAdoptedEvent             = "Adopted"
+	KindClusterRecreatingEvent          = "Recreating"
+	KindClusterReschedulingEvent        = "Rescheduling"
+	KindClusterLostEvent                = "ClusterLost"
+	KindClusterRetryingEvent            = "Retrying"
+	KindClusterRetryRequestedEvent      = "RetryRequested"
+	KindClusterProvisioningFailedEvent  = "ProvisioningFailed"
+	KindClusterDeletingEvent            = "Deleting"
+	KindClusterDeletedEvent             = "Deleted"
+	KindClusterRetainedEvent            = "Retained"
+	KindClusterExpiredEvent             = "Expired"
+	KindClusterInvalidTTLExtensionEvent = "InvalidTTLExtension"
+	KindAPIErrorEvent                   = "KindAPIError"
+
+	// KindClusterNameEventAnnotation is the annotation of the events with the name of the Kind cluster
+	KindClusterNameEventAnnotation = "infrastructure.cluster.x-k8s.io/kind-cluster-name"
+	// KindAPIHostEventAnnotation is the annotation of the events with the URL of the Kind Wrapper API
+	KindAPIHostEventAnnotation = "infrastructure.cluster.x-k8s.io/kind-api-host"
+)
+
+// recordEvent records an event of the KindCluster annotated with the name of its Kind cluster and the URL of its Kind Wrapper API
+// Both are appended to the message as well, so that they are shown by kubectl describe
+func (r *KindClusterReconciler) recordEvent(ctx context.Context, kindCluster *infrastructurev1alpha1.KindCluster, eventType, reason, messageFmt string, args ...interface{}) {
+	name := kindClusterName(kindCluster)
+	host := r.kindAPIHost(ctx, kindCluster)
+	message := fmt.Sprintf(messageFmt, args...)
+	r.Recorder.AnnotatedEventf(kindCluster, map[string]string{
+		KindClusterNameEventAnnotation: name,
+		KindAPIHostEventAnnotation:     host,
+	}, eventType, reason, "%s (Kind cluster %s, Kind Wrapper API %s)", message, name, host)
+}
+
+// kindAPIHost returns the URL of the Kind Wrapper API the Kind cluster is created on
+// The name of the KindHost is returned in case it cannot be retrieved
+func (r *KindClusterReconciler) kindAPIHost(ctx context.Context, kindCluster *infrastructurev1alpha1.KindCluster) string {
+	hostName := appliedHostName(kindCluster)
+	if hostName == "" {
+		if r.KindClient == nil {
+			return ""
+		}
+		return r.KindClient.host
+	}
+	var host infrastructurev1alpha1.KindHost
+	if err := r.Get(ctx, types.NamespacedName{Name: hostName}, &host); err != nil {
+		return hostName
+	}
+	return host.Spec.URL
+}
+
+// recordReadyEvent records the event of a KindCluster which became running
+func (r *KindClusterReconciler) recordReadyEvent(ctx context.Context, kindCluster *infrastructurev1alpha1.KindCluster, previousState infrastructurev1alpha1.KindClusterState) {
+	if previousState == infrastructurev1alpha1.KindClusterStateRunning {
+		return
+	}
+	r.recordEvent(ctx, kindCluster, corev1.EventTypeNormal, KindClusterReadyEvent, "Kind cluster is running")
+}
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"time"
+
+	infrastructurev1alpha1 "cluster-api-provider-kind/api/v1alpha1"
+)
+
+// Reasons of the events recorded for KindHosts with orphaned Kind clusters
+const (
+	KindClusterOrphanedEvent           = "OrphanedKindCluster"
+	KindClusterOrphanDeletedEvent      = "OrphanedKindClusterDeleted"
+	KindClusterOrphanDeleteFailedEvent = "OrphanedKindClusterDeleteFailed"
+)
+
+// kindClusterGCPageSize is the number of Kind clusters listed by a single request of the garbage collector
+const kindClusterGCPageSize = 100
+
+// orphanedKindClusters reports the Kind clusters whose owner KindCluster does not exist anymore by KindHost
+var orphanedKindClusters = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "capk_orphaned_kind_clusters",
+	Help: "Number of Kind clusters whose owner KindCluster does not exist anymore by KindHost",
+}, []string{"host"})
+
+// orphanedKindClustersDeleted counts the orphaned Kind clusters deleted by the garbage collector by KindHost
+var orphanedKindClustersDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "capk_orphaned_kind_clusters_deleted_total",
+	Help: "Number of orphaned Kind clusters deleted by the garbage collector by KindHost",
+}, []string{"host"})
+
+func init() {
+	metrics.Registry.MustRegister(orphanedKindClusters, orphanedKindClustersDeleted)
+}
+
+// KindClusterGarbageCollector periodically looks for orphaned Kind clusters, i.e. Kind clusters owned by a KindCluster which does not exist anymore
+// Kind clusters leak this way when their KindCluster gets deleted while the controller is down or the deletion fails
+// Orphans are reported by metrics and events of their KindHost and deleted once they stay orphaned for the grace period
+// Only Kind clusters recorded with the ManagerID of this management cluster are deleted, those recorded without a manager are only reported
+type KindClusterGarbageCollector struct {
+	client.Client
+	KindClient *KindClient
+	Recorder   record.EventRecorder
+	// Interval is the time between the scans of the Kind Wrapper APIs
+	Interval time.Duration
+	// GracePeriod is the time an orphaned Kind cluster is kept before it gets deleted, orphans are only reported if not set
+	GracePeriod time.Duration
+	// ManagerID identifies this management cluster, only Kind clusters recorded with this manager are collected
+	// Kind clusters of other management clusters sharing a Kind Wrapper API are skipped
+	ManagerID string
+	// CredentialsNamespace is the namespace of the provider, the credentials Secrets of the KindHosts are read from it only
+	CredentialsNamespace string
+
+	hostClients *kindHostClients
+	// orphanedSince records when the orphaned Kind clusters were found by the KindHost and the name of the Kind cluster
+	orphanedSince map[orphanKey]time.Time
+}
+
+type orphanKey struct {
+	host string
+	name string
+}
+
+// SetupWithManager adds the garbage collector to the Manager, it runs only on the leader
+// The credentials Secrets of the KindHosts are read directly, since Secrets are not cached by the Manager
+func (g *KindClusterGarbageCollector) SetupWithManager(mgr ctrl.Manager) error {
+	g.hostClients = newKindHostClients(mgr.GetAPIReader(), g.CredentialsNamespace)
+	g.orphanedSince = map[orphanKey]time.Time{}
+	return mgr.Add(g)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that orphans are not deleted by multiple replicas
+func (g *KindClusterGarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable and scans the Kind Wrapper APIs every interval until the context is done
+func (g *KindClusterGarbageCollector) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, g.collect, g.Interval)
+	return nil
+}
+
+// collect scans the default Kind Wrapper API and the Kind Wrapper APIs of all KindHosts for orphaned Kind clusters
+// The default Kind Wrapper API is scanned even if no KindCluster uses it anymore, so that its last orphans are found as well
+func (g *KindClusterGarbageCollector) collect(ctx context.Context) {
+	logger := log.FromContext(ctx).WithName("kindcluster-gc")
+
+	// The KindClusters are listed before the Kind clusters, so that Kind clusters of new KindClusters are never orphans
+	var kindClusters infrastructurev1alpha1.KindClusterList
+	if err := g.List(ctx, &kindClusters); err != nil {
+		logger.Error(err, "Failed to list KindClusters")
+		return
+	}
+	owners := sets.NewString()
+	for i := range kindClusters.Items {
+		owners.Insert(string(kindClusters.Items[i].UID))
+	}
+	var hosts infrastructurev1alpha1.KindHostList
+	if err := g.List(ctx, &hosts); err != nil {
+		logger.Error(err, "Failed to list KindHosts")
+		return
+	}
+
+	found := map[orphanKey]bool{}
+	if g.KindClient != nil {
+		g.collectHost(ctx, nil, g.KindClient, owners, found)
+	}
+	for i := range hosts.Items {
+		host := &hosts.Items[i]
+		kindClient, err := g.hostClients.forHost(ctx, host)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Failed to get Kind Wrapper API client of KindHost %s", host.Name))
+			continue
+		}
+		g.collectHost(ctx, host, kindClient, owners, found)
+	}
+	for key := range g.orphanedSince {
+		if !found[key] {
+			delete(g.orphanedSince, key)
+		}
+	}
+}
+
+// collectHost reports the orphaned Kind clusters of a Kind Wrapper API and deletes those orphaned for longer than the grace period
+// The host is nil for the default Kind Wrapper API, whose orphans are only logged
+func (g *KindClusterGarbageCollector) collectHost(ctx context.Context, host *infrastructurev1alpha1.KindHost, kindClient *KindClient, owners sets.String, found map[orphanKey]bool) {
+	hostName := ""
+	if host != nil {
+		hostName = host.Name
+	}
+	logger := log.FromContext(ctx).WithName("kindcluster-gc").WithValues("host", kindClient.host)
+
+	clusters, err := kindClient.ListClusters(ctx, KindClusterListOptions{PageSize: kindClusterGCPageSize})
+	if err == KindOwnershipNotSupportedError {
+		logger.Info("Kind Wrapper API does not record owners of Kind clusters, skipping")
+		orphanedKindClusters.DeleteLabelValues(hostName)
+		return
+	} else if err != nil {
+		logger.Error(err, "Failed to list Kind clusters")
+		return
+	}
+
+	orphans := 0
+	now := time.Now()
+	for _, cluster := range clusters {
+		// Kind clusters without an owner were not created by the provider or were released by the deletion policy
+		// Kind clusters of other management clusters are owned by KindClusters this one does not know about
+		if cluster.Owner == "" || owners.Has(cluster.Owner) || cluster.Manager != "" && cluster.Manager != g.ManagerID {
+			continue
+		}
+		key := orphanKey{host: hostName, name: cluster.Name}
+		found[key] = true
+		orphanedSince, ok := g.orphanedSince[key]
+		if !ok {
+			orphanedSince = now
+			g.orphanedSince[key] = now
+			logger.Info(fmt.Sprintf("Found orphaned Kind cluster %s of KindCluster %s", cluster.Name, cluster.Owner))
+			g.recordEvent(host, corev1.EventTypeWarning, KindClusterOrphanedEvent, "Kind cluster %s is orphaned, its KindCluster %s does not exist", cluster.Name, cluster.Owner)
+		}
+		// Kind clusters recorded without a manager may belong to any management cluster, so they are only reported
+		if g.GracePeriod <= 0 || cluster.Manager == "" || now.Sub(orphanedSince) < g.GracePeriod {
+			orphans++
+			continue
+		}
+		if err := kindClient.DeleteCluster(ctx, cluster.Name); err != nil && err != KindClusterNotFoundError {
+			logger.Error(err, fmt.Sprintf("Failed to delete orphaned Kind cluster %s", cluster.Name))
+			g.recordEvent(host, corev1.EventTypeWarning, KindClusterOrphanDeleteFailedEvent, "Failed to delete orphaned Kind cluster %s: %s", cluster.Name, err)
+			orphans++
+			continue
+		}
+		logger.Info(fmt.Sprintf("Deleted orphaned Kind cluster %s", cluster.Name))
+		g.recordEvent(host, corev1.EventTypeNormal, KindClusterOrphanDeletedEvent, "Deleted Kind cluster %s orphaned for %s", cluster.Name, now.Sub(orphanedSince).Round(time.Second))
+		orphanedKindClustersDeleted.WithLabelValues(hostName).Inc()
+		delete(g.orphanedSince, key)
+		delete(found, key)
+	}
+	orphanedKindClusters.WithLabelValues(hostName).Set(float64(orphans))
+}
+
+// recordEvent records an event of the KindHost, events of the default Kind Wrapper API are not recorded
+func (g *KindClusterGarbageCollector) recordEvent(host *infrastructurev1alpha1.KindHost, eventType, reason, messageFmt string, args ...interface{}) {
+	if host == nil || g.Recorder == nil {
+		return
+	}
+	g.Recorder.Eventf(host, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"cluster-api-provider-kind/api/v1alpha1"
+	"context"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"net/http"
+	"net/http/httptest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"strings"
+	"time"
+)
+
+var _ = Describe("KindCluster garbage collector", func() {
+
+	var server *httptest.Server
+	var deletePaths []string
+	var clusters string
+	var gc *KindClusterGarbageCollector
+	var recorder *record.FakeRecorder
+
+	BeforeEach(func() {
+		deletePaths = []string{}
+		clusters = `[{"name":"live","owner":"live-uid","manager":"mgmt"},{"name":"orphan","owner":"deleted-uid","manager":"mgmt"},{"name":"hand-made"}]`
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				_, _ = w.Write([]byte(clusters))
+			case http.MethodPut:
+				name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, kindApiPathCluster+"/"), kindApiPathOwner)
+				query := r.URL.Query()
+				clusters = fmt.Sprintf(`[{"name":%q,"owner":%q,"manager":%q}]`, name, query.Get(kindApiParamOwner), query.Get(kindApiParamManager))
+			case http.MethodDelete:
+				deletePaths = append(deletePaths, r.URL.Path)
+			}
+		}))
+		host := &v1alpha1.KindHost{ObjectMeta: metav1.ObjectMeta{Name: "lab-gc"}}
+		host.Spec.URL = server.URL
+		live := &v1alpha1.KindCluster{ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "default", UID: "live-uid"}}
+		live.Status.Host = host.Name
+		live.Status.AppliedSpecHash = "applied"
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(host, live).Build()
+		recorder = record.NewFakeRecorder(10)
+		gc = &KindClusterGarbageCollector{
+			Client:        reader,
+			Recorder:      recorder,
+			ManagerID:     "mgmt",
+			hostClients:   newKindHostClients(reader, "default"),
+			orphanedSince: map[orphanKey]time.Time{},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should only report orphaned Kind clusters without a grace period", func() {
+		gc.collect(context.Background())
+		gc.collect(context.Background())
+		Expect(deletePaths).To(BeEmpty())
+		Expect(testutil.ToFloat64(orphanedKindClusters.WithLabelValues("lab-gc"))).To(Equal(1.0))
+		Expect(recorder.Events).To(HaveLen(1))
+		Expect(<-recorder.Events).To(ContainSubstring("Kind cluster orphan is orphaned, its KindCluster deleted-uid does not exist"))
+	})
+
+	It("should delete orphaned Kind clusters after the grace period", func() {
+		gc.GracePeriod = 50 * time.Millisecond
+		deleted := testutil.ToFloat64(orphanedKindClustersDeleted.WithLabelValues("lab-gc"))
+
+		gc.collect(context.Background())
+		Expect(deletePaths).To(BeEmpty())
+
+		time.Sleep(gc.GracePeriod)
+		gc.collect(context.Background())
+		Expect(deletePaths).To(Equal([]string{kindApiPathCluster + "/orphan"}))
+		Expect(testutil.ToFloat64(orphanedKindClusters.WithLabelValues("lab-gc"))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(orphanedKindClustersDeleted.WithLabelValues("lab-gc"))).To(Equal(deleted + 1))
+		Expect(gc.orphanedSince).To(BeEmpty())
+	})
+
+	It("should skip Kind clusters of other management clusters", func() {
+		clusters = `[{"name":"foreign","owner":"deleted-uid","manager":"other"}]`
+		gc.GracePeriod = time.Nanosecond
+
+		gc.collect(context.Background())
+		gc.collect(context.Background())
+		Expect(deletePaths).To(BeEmpty())
+		Expect(gc.orphanedSince).To(BeEmpty())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should only report orphaned Kind clusters recorded without a manager", func() {
+		clusters = `[{"name":"legacy","owner":"deleted-uid"}]`
+		gc.GracePeriod = time.Nanosecond
+
+		gc.collect(context.Background())
+		gc.collect(context.Background())
+		Expect(deletePaths).To(BeEmpty())
+		Expect(gc.orphanedSince).To(HaveKey(orphanKey{host: "lab-gc", name: "legacy"}))
+		Expect(testutil.ToFloat64(orphanedKindClusters.WithLabelValues("lab-gc"))).To(Equal(1.0))
+	})
+
+	It("should keep the Kind cluster of a KindCluster moved by clusterctl", func() {
+		clusters = `[{"name":"moved","owner":"moved-uid","manager":"mgmt"}]`
+		moved := &v1alpha1.KindCluster{ObjectMeta: metav1.ObjectMeta{Name: "moved", Namespace: "default", UID: "moved-uid"}}
+		moved.Status.Host = "lab-gc"
+		moved.Status.KindClusterName = "moved"
+		moved.Status.AppliedSpecHash = "applied"
+		kindClient := newKindClient(server.URL, &http.Client{}, "")
+		gc.GracePeriod = time.Nanosecond
+
+		// The paused KindCluster hands over the owner before clusterctl removes it, it is never stored by the fake client
+		Expect(handOverKindCluster(context.Background(), kindClient, moved)).To(Succeed())
+		Expect(moved.Status.OwnerHandedOver).To(BeTrue())
+		Expect(clusters).To(ContainSubstring(`"manager":""`))
+
+		gc.collect(context.Background())
+		gc.collect(context.Background())
+		Expect(deletePaths).To(BeEmpty())
+		Expect(gc.orphanedSince).To(HaveKey(orphanKey{host: "lab-gc", name: "moved"}))
+
+		// The moved KindCluster records its new UID with the identity of the target management cluster
+		moved.UID = "target-uid"
+		Expect(setKindClusterOwner(context.Background(), kindClient, kindClusterName(moved), kindClusterOwner(moved, "target"))).To(Succeed())
+
+		gc.collect(context.Background())
+		Expect(deletePaths).To(BeEmpty())
+		Expect(gc.orphanedSince).To(BeEmpty())
+	})
+
+	It("should scan the default Kind Wrapper API even if no KindCluster uses it", func() {
+		defaultServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[{"name":"default-orphan","owner":"deleted-uid","manager":"mgmt"}]`))
+		}))
+		defer defaultServer.Close()
+		gc.KindClient = newKindClient(defaultServer.URL, &http.Client{}, "")
+
+		gc.collect(context.Background())
+		Expect(gc.orphanedSince).To(HaveKey(orphanKey{host: "", name: "default-orphan"}))
+		Expect(gc.orphanedSince).To(HaveKey(orphanKey{host: "lab-gc", name: "orphan"}))
+		Expect(testutil.ToFloat64(orphanedKindClusters.WithLabelValues(""))).To(Equal(1.0))
+	})
+})
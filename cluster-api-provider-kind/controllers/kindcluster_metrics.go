@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"strconv"
+	"time"
+
+	infrastructurev1alpha1 "cluster-api-provider-kind/api/v1alpha1"
+)
+
+const (
+	kindClusterCollectorTimeout = 10 * time.Second
+
+	kindAPIResultError       = "error"
+	kindAPIResultCircuitOpen = "circuit_open"
+)
+
+// kindAPIRequests counts the requests of the Kind Wrapper APIs by the endpoint and the result,
+// which is the HTTP status code, error for transport errors or circuit_open for requests stopped by the circuit breaker
+var kindAPIRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "capk_kind_api_requests_total",
+	Help: "Number of requests of the Kind Wrapper API by endpoint and result",
+}, []string{"host", "endpoint", "result"})
+
+// kindClusterProvisioningDuration observes the time from the start of the attempt to create a Kind cluster until it is running
+var kindClusterProvisioningDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "capk_kindcluster_provisioning_duration_seconds",
+	Help:    "Time from the start of the attempt to create the Kind cluster of a KindCluster until it is running",
+	Buckets: prometheus.ExponentialBuckets(15, 2, 8),
+}, []string{"host"})
+
+var (
+	kindClustersDesc = prometheus.NewDesc("capk_kindclusters",
+		"Number of KindClusters by state and KindHost",
+		[]string{"state", "host"}, nil)
+	kindClusterProvisioningRetriesDesc = prometheus.NewDesc("capk_kindcluster_provisioning_retries",
+		"Number of retries of the creation of the Kind cluster of a KindCluster",
+		[]string{"namespace", "name", "host"}, nil)
+)
+
+func init() {
+	metrics.Registry.MustRegister(kindAPIRequests, kindClusterProvisioningDuration)
+}
+
+// kindAPIRequestResult returns the result of a request of the Kind Wrapper API reported by the request metric
+func kindAPIRequestResult(response *kindAPIResponse, err error) string {
+	if errors.Is(err, KindAPICircuitOpenError) {
+		return kindAPIResultCircuitOpen
+	}
+	if err != nil {
+		return kindAPIResultError
+	}
+	return strconv.Itoa(response.StatusCode)
+}
+
+// observeProvisioningDuration observes the provisioning time of a KindCluster whose Kind cluster created by the controller became running
+// The time is measured from the start of the successful attempt, so that failed attempts and recreated Kind clusters
+// do not count the time before it. Adopted Kind clusters and running Kind clusters checked again are not observed
+func observeProvisioningDuration(kindCluster *infrastructurev1alpha1.KindCluster, previousState infrastructurev1alpha1.KindClusterState) {
+	provisioning := kindCluster.Status.Provisioning
+	if previousState == infrastructurev1alpha1.KindClusterStateRunning || provisioning == nil || provisioning.StartTime == nil {
+		return
+	}
+	kindClusterProvisioningDuration.WithLabelValues(kindCluster.Status.Host).Observe(time.Since(provisioning.StartTime.Time).Seconds())
+}
+
+// kindClusterCollector reports the KindClusters by state and KindHost and their provisioning retries
+// The KindClusters are listed on every scrape, so that deleted KindClusters are not reported anymore
+type kindClusterCollector struct {
+	reader           client.Reader
+	watchFilterValue string
+}
+
+// RegisterKindClusterCollector registers the collector of the KindCluster metrics, which lists the KindClusters by the reader
+// Only KindClusters with the watch filter label are reported in case the watch filter is set
+func RegisterKindClusterCollector(reader client.Reader, watchFilterValue string) error {
+	return metrics.Registry.Register(&kindClusterCollector{reader: reader, watchFilterValue: watchFilterValue})
+}
+
+// Describe implements prometheus.Collector
+func (c *kindClusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- kindClustersDesc
+	ch <- kindClusterProvisioningRetriesDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *kindClusterCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), kindClusterCollectorTimeout)
+	defer cancel()
+	var kindClusters infrastructurev1alpha1.KindClusterList
+	if err := c.reader.List(ctx, &kindClusters, watchFilterListOptions(c.watchFilterValue)...); err != nil {
+		ch <- prometheus.NewInvalidMetric(kindClustersDesc, err)
+		return
+	}
+
+	type stateHost struct {
+		state infrastructurev1alpha1.KindClusterState
+		host  string
+	}
+	counts := map[stateHost]int{}
+	for _, kindCluster := range kindClusters.Items {
+		counts[stateHost{state: kindCluster.Status.State, host: kindCluster.Status.Host}]++
+		if provisioning := kindCluster.Status.Provisioning; provisioning != nil && provisioning.Attempts > 0 {
+			ch <- prometheus.MustNewConstMetric(kindClusterProvisioningRetriesDesc, prometheus.GaugeValue,
+				float64(provisioning.Attempts-1), kindCluster.Namespace, kindCluster.Name, kindCluster.Status.Host)
+		}
+	}
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(kindClustersDesc, prometheus.GaugeValue, float64(count), string(key.state), key.host)
+	}
+}
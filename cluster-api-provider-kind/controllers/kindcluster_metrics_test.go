@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"cluster-api-provider-kind/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"strings"
+	"time"
+)
+
+var _ = Describe("KindCluster metrics", func() {
+
+	newKindCluster := func(name string, state v1alpha1.KindClusterState, host string, attempts int32) *v1alpha1.KindCluster {
+		kindCluster := &v1alpha1.KindCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		kindCluster.Status.State = state
+		kindCluster.Status.Host = host
+		if attempts > 0 {
+			kindCluster.Status.Provisioning = &v1alpha1.KindClusterProvisioningStatus{Attempts: attempts}
+		}
+		return kindCluster
+	}
+
+	It("should report KindClusters by state and host and their provisioning retries", func() {
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			newKindCluster("running-a", v1alpha1.KindClusterStateRunning, "lab-a", 1),
+			newKindCluster("running-b", v1alpha1.KindClusterStateRunning, "lab-a", 0),
+			newKindCluster("retrying", v1alpha1.KindClusterStatePending, "lab-b", 3),
+		).Build()
+
+		expected := `
+# HELP capk_kindcluster_provisioning_retries Number of retries of the creation of the Kind cluster of a KindCluster
+# TYPE capk_kindcluster_provisioning_retries gauge
+capk_kindcluster_provisioning_retries{host="lab-a",name="running-a",namespace="default"} 0
+capk_kindcluster_provisioning_retries{host="lab-b",name="retrying",namespace="default"} 2
+# HELP capk_kindclusters Number of KindClusters by state and KindHost
+# TYPE capk_kindclusters gauge
+capk_kindclusters{host="lab-a",state="Running"} 2
+capk_kindclusters{host="lab-b",state="Pending"} 1
+`
+		Expect(testutil.CollectAndCompare(&kindClusterCollector{reader: reader}, strings.NewReader(expected))).To(Succeed())
+	})
+
+	It("should observe the provisioning duration only when a created Kind cluster becomes running", func() {
+		series := testutil.CollectAndCount(kindClusterProvisioningDuration)
+		created := newKindCluster("created", v1alpha1.KindClusterStateRunning, "lab-created", 1)
+		created.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+		startTime := metav1.NewTime(time.Now().Add(-time.Minute))
+		created.Status.Provisioning.StartTime = &startTime
+		adopted := newKindCluster("adopted", v1alpha1.KindClusterStateRunning, "lab-adopted", 0)
+
+		By("skipping running Kind clusters checked again and adopted Kind clusters")
+		observeProvisioningDuration(created, v1alpha1.KindClusterStateRunning)
+		observeProvisioningDuration(adopted, v1alpha1.KindClusterStatePending)
+		Expect(testutil.CollectAndCount(kindClusterProvisioningDuration)).To(Equal(series))
+
+		By("observing the Kind cluster created by the controller")
+		observeProvisioningDuration(created, v1alpha1.KindClusterStatePending)
+		Expect(testutil.CollectAndCount(kindClusterProvisioningDuration)).To(Equal(series + 1))
+
+		By("measuring the duration from the start of the current attempt")
+		observed := &dto.Metric{}
+		Expect(kindClusterProvisioningDuration.WithLabelValues("lab-created").(prometheus.Metric).Write(observed)).To(Succeed())
+		Expect(observed.GetHistogram().GetSampleSum()).To(BeNumerically("~", time.Minute.Seconds(), 10))
+	})
+})
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"cluster-api-provider-kind/api/v1alpha1"
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"net/http"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"strings"
+	"time"
+)
+
+var _ = Describe("KindCluster move", func() {
+
+	// moveObject creates a copy of the object in the target cluster the way clusterctl move does, the status is not copied
+	moveObject := func(targetClient client.Client, source, target client.Object) {
+		target.SetName(source.GetName())
+		target.SetNamespace(source.GetNamespace())
+		target.SetLabels(source.GetLabels())
+		target.SetAnnotations(source.GetAnnotations())
+		target.SetFinalizers(source.GetFinalizers())
+		Expect(targetClient.Create(context.Background(), target)).Should(Succeed())
+	}
+
+	// deleteSourceObject removes the finalizers of the object in the source cluster and deletes it the way clusterctl move does
+	deleteSourceObject := func(object client.Object) {
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(object), object)).To(Succeed())
+			object.SetFinalizers(nil)
+			g.Expect(k8sClient.Update(context.Background(), object)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+		Expect(k8sClient.Delete(context.Background(), object)).Should(Succeed())
+	}
+
+	It("should keep the Kind cluster running when the KindCluster is moved to another management cluster", func() {
+		hostMockKindApiServer := &MockKindApiServer{}
+		hostMockKindApiServer.Init()
+		defer hostMockKindApiServer.server.Close()
+		hostMockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		hostMockKindApiServer.statusResponses = []MockKindApiServerResponse{NotFoundMockApiResponse}
+
+		By("creating the Kind cluster from the source management cluster")
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-move"},
+			Spec:       v1alpha1.KindHostSpec{URL: hostMockKindApiServer.server.URL},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(context.Background(), host))).Should(Succeed())
+		}()
+
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "move-cluster", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{APIVersion: v1alpha1.GroupVersion.String(), Kind: "KindCluster", Name: "move-cluster", Namespace: "default"},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), cluster)).Should(Succeed())
+
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "move-cluster",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+					UID:        cluster.UID,
+				}},
+			},
+			Spec: v1alpha1.KindClusterSpec{
+				Nodes:   []v1alpha1.KindClusterNode{{Role: "control-plane"}},
+				HostRef: &v1alpha1.KindHostReference{Name: host.Name},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		key := types.NamespacedName{Name: kindCluster.Name, Namespace: kindCluster.Namespace}
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.Ready).To(BeTrue())
+			g.Expect(fetched.Status.Host).To(Equal(host.Name))
+		}, 20*time.Second, time.Second).Should(Succeed())
+		Expect(fetched.Annotations).To(HaveKeyWithValue(v1alpha1.KindClusterHostAnnotation, host.Name))
+		Expect(hostMockKindApiServer.CreatePayloads()).To(HaveLen(1))
+
+		By("pausing the owner Cluster in the source management cluster")
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			cluster.Spec.Paused = true
+			g.Expect(k8sClient.Update(context.Background(), cluster)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		By("starting the target management cluster")
+		targetEnv := &envtest.Environment{
+			CRDDirectoryPaths:     testEnv.CRDDirectoryPaths,
+			ErrorIfCRDPathMissing: true,
+		}
+		targetCfg, err := targetEnv.Start()
+		Expect(err).NotTo(HaveOccurred())
+		defer func() {
+			Expect(targetEnv.Stop()).To(Succeed())
+		}()
+		targetClient, err := client.New(targetCfg, client.Options{Scheme: scheme.Scheme})
+		Expect(err).NotTo(HaveOccurred())
+
+		targetManager, err := ctrl.NewManager(targetCfg, ctrl.Options{Scheme: scheme.Scheme, MetricsBindAddress: "0"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect((&KindClusterReconciler{
+			Client:     targetManager.GetClient(),
+			KindClient: newKindClient(mockKindApiServer.server.URL, http.DefaultClient, ""),
+			Scheme:     targetManager.GetScheme(),
+		}).SetupWithManager(targetManager)).To(Succeed())
+		targetCtx, targetCancel := context.WithCancel(context.Background())
+		defer targetCancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(targetManager.Start(targetCtx)).To(Succeed())
+		}()
+
+		By("moving the objects to the target management cluster")
+		Expect(k8sClient.Get(context.Background(), key, kindCluster)).To(Succeed())
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		moveObject(targetClient, host, &v1alpha1.KindHost{Spec: host.Spec})
+		targetCluster := &clusterv1.Cluster{Spec: cluster.Spec}
+		moveObject(targetClient, cluster, targetCluster)
+		targetKindCluster := &v1alpha1.KindCluster{Spec: kindCluster.Spec}
+		targetKindCluster.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+			Name:       targetCluster.Name,
+			UID:        targetCluster.UID,
+		}}
+		moveObject(targetClient, kindCluster, targetKindCluster)
+
+		By("deleting the objects from the source management cluster")
+		deleteSourceObject(kindCluster)
+		deleteSourceObject(cluster)
+		deleteSourceObject(host)
+		Eventually(func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(context.Background(), key, &v1alpha1.KindCluster{}))
+		}, 10*time.Second, time.Second).Should(BeTrue())
+
+		By("unpausing the owner Cluster in the target management cluster")
+		Eventually(func(g Gomega) {
+			g.Expect(targetClient.Get(context.Background(), client.ObjectKeyFromObject(targetCluster), targetCluster)).To(Succeed())
+			targetCluster.Spec.Paused = false
+			g.Expect(targetClient.Update(context.Background(), targetCluster)).To(Succeed())
+		}, 10*time.Second, time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(targetClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.Ready).To(BeTrue())
+			g.Expect(fetched.Status.Host).To(Equal(host.Name))
+			g.Expect(fetched.Status.AppliedSpecHash).NotTo(BeEmpty())
+		}, 20*time.Second, time.Second).Should(Succeed())
+
+		Consistently(func(g Gomega) {
+			g.Expect(hostMockKindApiServer.CreatePayloads()).To(HaveLen(1))
+			g.Expect(hostMockKindApiServer.DeletePaths()).To(BeEmpty())
+			for _, path := range mockKindApiServer.DeletePaths() {
+				g.Expect(strings.HasSuffix(path, "/default-move-cluster")).To(BeFalse())
+			}
+		}, 3*time.Second, time.Second).Should(Succeed())
+	})
+})
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"cluster-api-provider-kind/api/v1alpha1"
+	"context"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
+)
+
+// kindHostClients creates and caches a KindClient for every KindHost
+// A cached client is replaced once the KindHost or its credentials Secret changes
+// The credentials Secrets are read from the namespace of the provider only
+type kindHostClients struct {
+	reader    client.Reader
+	namespace string
+	mutex     sync.Mutex
+	clients   map[string]kindHostClient
+}
+
+type kindHostClient struct {
+	version string
+	client  *KindClient
+}
+
+// newKindHostClients creates a new instance of kindHostClients reading the credentials Secrets of the namespace with the specified reader
+func newKindHostClients(reader client.Reader, namespace string) *kindHostClients {
+	return &kindHostClients{reader: reader, namespace: namespace, clients: map[string]kindHostClient{}}
+}
+
+// forHost returns a KindClient configured by the KindHost
+func (c *kindHostClients) forHost(ctx context.Context, host *v1alpha1.KindHost) (*KindClient, error) {
+	token := ""
+	version := fmt.Sprintf("%s/%d", host.UID, host.Generation)
+	if ref := host.Spec.CredentialsSecretRef; ref != nil {
+		key, err := c.credentialsSecretKey(host)
+		if err != nil {
+			return nil, err
+		}
+		var secret corev1.Secret
+		if err := c.reader.Get(ctx, key, &secret); err != nil {
+			return nil, err
+		}
+		token = string(secret.Data[v1alpha1.KindHostCredentialsTokenKey])
+		if token == "" {
+			return nil, fmt.Errorf("key %s not found in Secret %s", v1alpha1.KindHostCredentialsTokenKey, key)
+		}
+		version = fmt.Sprintf("%s/%s", version, secret.ResourceVersion)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if cached, ok := c.clients[host.Name]; ok && cached.version == version {
+		return cached.client, nil
+	}
+	kindClient, err := NewKindClientForHost(host.Spec.URL, token, host.Spec.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	c.clients[host.Name] = kindHostClient{version: version, client: kindClient}
+	return kindClient, nil
+}
+
+// credentialsSecretKey returns the key of the credentials Secret of the KindHost in the namespace of the provider
+func (c *kindHostClients) credentialsSecretKey(host *v1alpha1.KindHost) (types.NamespacedName, error) {
+	if c.namespace == "" {
+		return types.NamespacedName{}, fmt.Errorf("namespace of the credentials Secret %s of KindHost %s is not configured", host.Spec.CredentialsSecretRef.Name, host.Name)
+	}
+	return types.NamespacedName{Namespace: c.namespace, Name: host.Spec.CredentialsSecretRef.Name}, nil
+}
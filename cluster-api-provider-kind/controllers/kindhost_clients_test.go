@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"cluster-api-provider-kind/api/v1alpha1"
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"net/http"
+	"net/http/httptest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("KindHost clients", func() {
+
+	var server *httptest.Server
+	var authorization string
+	var host *v1alpha1.KindHost
+
+	BeforeEach(func() {
+		authorization = ""
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte(`{"cpus":8}`))
+		}))
+		host = &v1alpha1.KindHost{ObjectMeta: metav1.ObjectMeta{Name: "lab-clients"}}
+		host.Spec.URL = server.URL
+		host.Spec.CredentialsSecretRef = &v1alpha1.KindHostSecretReference{Name: "lab-credentials"}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should read the credentials Secret from the namespace of the provider only", func() {
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "lab-credentials", Namespace: "capk-system"},
+				Data:       map[string][]byte{v1alpha1.KindHostCredentialsTokenKey: []byte("provider")},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "lab-credentials", Namespace: "default"},
+				Data:       map[string][]byte{v1alpha1.KindHostCredentialsTokenKey: []byte("other")},
+			},
+		).Build()
+
+		kindClient, err := newKindHostClients(reader, "capk-system").forHost(context.Background(), host)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = kindClient.GetHostStats(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(authorization).To(Equal("Bearer provider"))
+
+		_, err = newKindHostClients(reader, "").forHost(context.Background(), host)
+		Expect(err).To(MatchError(ContainSubstring("is not configured")))
+	})
+})
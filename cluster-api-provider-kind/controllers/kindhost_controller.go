@@ -0,0 +1,197 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"time"
+
+	infrastructurev1alpha1 "cluster-api-provider-kind/api/v1alpha1"
+)
+
+const kindHostReportInterval = 30 * time.Second
+
+// KindHostReconciler reports the resources and usage of a KindHost in its status
+type KindHostReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// WatchFilterValue limits the reconciliation to KindHosts with the cluster.x-k8s.io/watch-filter label of this value
+	WatchFilterValue string
+	// MaxConcurrentReconciles is the number of KindHosts reconciled concurrently, one if not set
+	MaxConcurrentReconciles int
+	// CredentialsNamespace is the namespace of the provider, the credentials Secrets of the KindHosts are read from it only
+	CredentialsNamespace string
+
+	hostClients *kindHostClients
+	apiReader   client.Reader
+}
+
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindhosts,verbs=get;list;watch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindhosts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusters,verbs=get;list;watch
+
+// Reconcile reads the resources of the host from the Kind Wrapper API and counts the Kind clusters created on it
+// The KindHost is reconciled periodically, since the resources change without any event
+func (r *KindHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var host infrastructurev1alpha1.KindHost
+	if err := r.Get(ctx, req.NamespacedName, &host); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, fmt.Sprintf("Failed to retrieve KindHost %s", req.Name))
+		return ctrl.Result{}, err
+	}
+
+	helper, err := patch.NewHelper(&host, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var kindClusters infrastructurev1alpha1.KindClusterList
+	if err := r.List(ctx, &kindClusters); err != nil {
+		return ctrl.Result{}, err
+	}
+	clusters, nodes := hostUsage(kindClusters.Items, host.Name, "")
+	host.Status.Clusters = int32(clusters)
+	host.Status.Nodes = int32(nodes)
+
+	if err := r.reconcileCredentialsSecret(ctx, &host); err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to label credentials Secret of KindHost %s", host.Name))
+		return ctrl.Result{}, err
+	}
+
+	stats, capabilities, err := r.hostStats(ctx, &host)
+	if err != nil {
+		logger.Info(fmt.Sprintf("KindHost %s is not available: %s", host.Name, err))
+		reason := infrastructurev1alpha1.KindHostUnreachableReason
+		if goerrors.Is(err, KindAPICircuitOpenError) {
+			reason = infrastructurev1alpha1.KindAPICircuitOpenReason
+		}
+		conditions.MarkFalse(&host, infrastructurev1alpha1.KindHostAvailableCondition, reason, clusterv1.ConditionSeverityWarning, err.Error())
+	} else {
+		host.Status.Resources = &infrastructurev1alpha1.KindHostResources{
+			CPU: infrastructurev1alpha1.KindHostResource{
+				Total: *resource.NewMilliQuantity(stats.CPU.Total, resource.DecimalSI),
+				Free:  *resource.NewMilliQuantity(stats.CPU.Free, resource.DecimalSI),
+			},
+			Memory: bytesResource(stats.Memory),
+			Disk:   bytesResource(stats.Disk),
+		}
+		host.Status.RuntimeVersion = stats.RuntimeVersion
+		host.Status.KindVersion = stats.KindVersion
+		host.Status.Features = capabilities.Features
+		now := metav1.Now()
+		host.Status.LastReportTime = &now
+		conditions.MarkTrue(&host, infrastructurev1alpha1.KindHostAvailableCondition)
+	}
+
+	err = helper.Patch(ctx, &host, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+		infrastructurev1alpha1.KindHostAvailableCondition,
+	}})
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to update KindHost %s", host.Name))
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: kindHostReportInterval}, nil
+}
+
+// hostStats reads the resources and capabilities of the host from its Kind Wrapper API
+// Kind Wrapper API versions which do not report capabilities are reported without any features
+func (r *KindHostReconciler) hostStats(ctx context.Context, host *infrastructurev1alpha1.KindHost) (KindHostStats, KindCapabilities, error) {
+	kindClient, err := r.hostClients.forHost(ctx, host)
+	if err != nil {
+		return KindHostStats{}, KindCapabilities{}, err
+	}
+	stats, err := kindClient.GetHostStats(ctx)
+	if err != nil {
+		return KindHostStats{}, KindCapabilities{}, err
+	}
+	capabilities, err := kindClient.GetCapabilities(ctx)
+	if err != nil && err != KindCapabilitiesNotSupportedError {
+		return KindHostStats{}, KindCapabilities{}, err
+	}
+	return stats, capabilities, nil
+}
+
+// reconcileCredentialsSecret labels the credentials Secret of the KindHost to be moved by clusterctl together with the KindHost
+// Only the Secrets in the namespace of the provider are labeled, the credentials-role Role grants to read and patch them there only
+// A missing Secret is skipped, it is reported by the availability of the KindHost
+func (r *KindHostReconciler) reconcileCredentialsSecret(ctx context.Context, host *infrastructurev1alpha1.KindHost) error {
+	ref := host.Spec.CredentialsSecretRef
+	if ref == nil {
+		return nil
+	}
+	key, err := r.hostClients.credentialsSecretKey(host)
+	if err != nil {
+		return err
+	}
+	var secret corev1.Secret
+	if err := r.apiReader.Get(ctx, key, &secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if _, ok := secret.Labels[clusterctlv1.ClusterctlMoveLabelName]; ok {
+		return nil
+	}
+	original := secret.DeepCopy()
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[clusterctlv1.ClusterctlMoveLabelName] = ""
+	return r.Patch(ctx, &secret, client.MergeFrom(original))
+}
+
+// bytesResource converts a resource measured in bytes by the Kind Wrapper API to quantities
+func bytesResource(stats KindHostResourceStats) infrastructurev1alpha1.KindHostResource {
+	return infrastructurev1alpha1.KindHostResource{
+		Total: *resource.NewQuantity(stats.Total, resource.BinarySI),
+		Free:  *resource.NewQuantity(stats.Free, resource.BinarySI),
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status updates are ignored, the KindHost is requeued periodically instead
+// KindHosts without the watch filter label are filtered out in case the watch filter is set
+func (r *KindHostReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.apiReader = mgr.GetAPIReader()
+	r.hostClients = newKindHostClients(r.apiReader, r.CredentialsNamespace)
+	logger := mgr.GetLogger().WithName("kindhost")
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrastructurev1alpha1.KindHost{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		WithEventFilter(predicates.ResourceHasFilterLabel(logger, r.WatchFilterValue)).
+		Complete(r)
+}
@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"cluster-api-provider-kind/api/v1alpha1"
+	"context"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"time"
+)
+
+var _ = Describe("KindHost Controller", func() {
+
+	var spec v1alpha1.KindClusterSpec
+
+	createHost := func(name string, hostLabels map[string]string, server *MockKindApiServer) *v1alpha1.KindHost {
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: hostLabels},
+			Spec:       v1alpha1.KindHostSpec{URL: server.server.URL},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		return host
+	}
+
+	BeforeEach(func() {
+		spec = v1alpha1.KindClusterSpec{
+			Nodes: []v1alpha1.KindClusterNode{{
+				Role: "control-plane",
+			}},
+		}
+	})
+
+	It("should report resources and availability of the KindHost", func() {
+		hostMockKindApiServer := &MockKindApiServer{}
+		hostMockKindApiServer.Init()
+		defer hostMockKindApiServer.server.Close()
+
+		host := createHost("lab-report", nil, hostMockKindApiServer)
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), host)).Should(Succeed())
+		}()
+
+		fetched := &v1alpha1.KindHost{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: host.Name}, fetched)).To(Succeed())
+			g.Expect(conditions.IsTrue(fetched, v1alpha1.KindHostAvailableCondition)).To(BeTrue())
+			g.Expect(fetched.Status.Resources).NotTo(BeNil())
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		Expect(fetched.Status.Resources.CPU.Free.Equal(resource.MustParse("6"))).To(BeTrue())
+		Expect(fetched.Status.Resources.Memory.Free.Equal(resource.MustParse("8Gi"))).To(BeTrue())
+		Expect(fetched.Status.Resources.Disk.Total.Equal(resource.MustParse("100Gi"))).To(BeTrue())
+		Expect(fetched.Status.LastReportTime).NotTo(BeNil())
+		Expect(fetched.Status.KindVersion).To(Equal("0.18.0"))
+		Expect(fetched.Status.RuntimeVersion).To(Equal("20.10.17"))
+		Expect(fetched.Status.Features).To(ContainElement("hostInfo"))
+	})
+
+	It("should label the credentials Secret of the KindHost to be moved by clusterctl", func() {
+		hostMockKindApiServer := &MockKindApiServer{}
+		hostMockKindApiServer.Init()
+		defer hostMockKindApiServer.server.Close()
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-move-credentials", Namespace: "default"},
+			Data:       map[string][]byte{v1alpha1.KindHostCredentialsTokenKey: []byte("secret")},
+		}
+		Expect(k8sClient.Create(context.Background(), secret)).Should(Succeed())
+		host := &v1alpha1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "lab-move"},
+			Spec: v1alpha1.KindHostSpec{
+				URL:                  hostMockKindApiServer.server.URL,
+				CredentialsSecretRef: &v1alpha1.KindHostSecretReference{Name: secret.Name},
+			},
+		}
+		Expect(k8sClient.Create(context.Background(), host)).Should(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), host)).Should(Succeed())
+		}()
+
+		Eventually(func(g Gomega) {
+			fetched := &corev1.Secret{}
+			g.Expect(k8sClient.Get(context.Background(), types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, fetched)).To(Succeed())
+			g.Expect(fetched.Labels).To(HaveKey(clusterctlv1.ClusterctlMoveLabelName))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+	})
+
+	It("should schedule a KindCluster without a host reference on a KindHost matched by the host selector", func() {
+		matchedMockKindApiServer := &MockKindApiServer{}
+		matchedMockKindApiServer.Init()
+		defer matchedMockKindApiServer.server.Close()
+		matchedMockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		matchedMockKindApiServer.statusResponses = []MockKindApiServerResponse{NotFoundMockApiResponse}
+		otherMockKindApiServer := &MockKindApiServer{}
+		otherMockKindApiServer.Init()
+		defer otherMockKindApiServer.server.Close()
+
+		for _, host := range []*v1alpha1.KindHost{
+			createHost("lab-select-a", map[string]string{"pool": "select", "zone": "a"}, otherMockKindApiServer),
+			createHost("lab-select-b", map[string]string{"pool": "select", "zone": "b"}, matchedMockKindApiServer),
+		} {
+			defer func(host *v1alpha1.KindHost) {
+				Expect(k8sClient.Delete(context.Background(), host)).Should(Succeed())
+			}(host)
+		}
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-scheduled",
+			Namespace: "default",
+		}
+
+		spec.Scheduling = &v1alpha1.KindClusterScheduling{
+			HostSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "select", "zone": "b"}},
+		}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		Expect(fetched.Status.Host).To(Equal("lab-select-b"))
+		Expect(fetched.Status.Scheduling).NotTo(BeNil())
+		Expect(fetched.Status.Scheduling.Host).To(Equal("lab-select-b"))
+		Expect(fetched.Status.Scheduling.Message).To(ContainSubstring("KindHost lab-select-b selected"))
+		Expect(conditions.IsTrue(fetched, v1alpha1.KindHostScheduledCondition)).To(BeTrue())
+		Expect(matchedMockKindApiServer.CreatePayloads()).To(HaveLen(1))
+		Expect(otherMockKindApiServer.CreatePayloads()).To(BeEmpty())
+	})
+
+	It("should wait for a KindHost matched by the host selector", func() {
+		host := createHost("lab-unmatched", map[string]string{"pool": "other"}, mockKindApiServer)
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), host)).Should(Succeed())
+		}()
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-unscheduled",
+			Namespace: "default",
+		}
+
+		spec.Scheduling = &v1alpha1.KindClusterScheduling{
+			HostSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "missing"}},
+		}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(conditions.GetReason(fetched, v1alpha1.KindHostScheduledCondition)).To(Equal(v1alpha1.NoKindHostAvailableReason))
+			g.Expect(fetched.Status.Scheduling).NotTo(BeNil())
+		}, 20*time.Second, 2*time.Second).Should(Succeed())
+
+		Expect(fetched.Status.Scheduling.Host).To(BeEmpty())
+		Expect(fetched.Status.Scheduling.Message).To(ContainSubstring("lab-unmatched: not matched by the host selector"))
+		Expect(fetched.Status.AppliedSpecHash).To(BeEmpty())
+	})
+
+	It("should reschedule a KindCluster which failed on the selected KindHost", func() {
+		failingMockKindApiServer := &MockKindApiServer{}
+		failingMockKindApiServer.Init()
+		defer failingMockKindApiServer.server.Close()
+		failingMockKindApiServer.SetDefaultStatusResponse(FailedStatusMockApiResponse)
+		failingMockKindApiServer.statusResponses = []MockKindApiServerResponse{NotFoundMockApiResponse}
+		runningMockKindApiServer := &MockKindApiServer{}
+		runningMockKindApiServer.Init()
+		defer runningMockKindApiServer.server.Close()
+		runningMockKindApiServer.SetDefaultStatusResponse(RunningStatusMockApiResponse)
+		runningMockKindApiServer.statusResponses = []MockKindApiServerResponse{NotFoundMockApiResponse}
+
+		for _, host := range []*v1alpha1.KindHost{
+			createHost("lab-reschedule-a", map[string]string{"pool": "reschedule"}, failingMockKindApiServer),
+			createHost("lab-reschedule-b", map[string]string{"pool": "reschedule"}, runningMockKindApiServer),
+		} {
+			defer func(host *v1alpha1.KindHost) {
+				Expect(k8sClient.Delete(context.Background(), host)).Should(Succeed())
+			}(host)
+		}
+
+		key := types.NamespacedName{
+			Name:      "kind-cluster-rescheduled",
+			Namespace: "default",
+		}
+
+		spec.Scheduling = &v1alpha1.KindClusterScheduling{
+			HostSelector:        &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "reschedule"}},
+			RescheduleOnFailure: true,
+		}
+		kindCluster := &v1alpha1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+			Spec: spec,
+		}
+
+		Expect(k8sClient.Create(context.Background(), kindCluster)).Should(Succeed())
+
+		fetched := &v1alpha1.KindCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.Background(), key, fetched)).To(Succeed())
+			g.Expect(fetched.Status.State).To(Equal(v1alpha1.KindClusterStateRunning))
+		}, 30*time.Second, 2*time.Second).Should(Succeed())
+
+		Expect(fetched.Status.Host).To(Equal("lab-reschedule-b"))
+		Expect(fetched.Status.Scheduling.FailedHosts).To(Equal([]string{"lab-reschedule-a"}))
+		Expect(fetched.Status.FailureReason).To(BeNil())
+		Expect(failingMockKindApiServer.CreatePayloads()).To(HaveLen(1))
+		Expect(runningMockKindApiServer.CreatePayloads()).To(HaveLen(1))
+	})
+})
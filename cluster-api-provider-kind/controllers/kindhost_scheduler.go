@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"cluster-api-provider-kind/api/v1alpha1"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sort"
+	"strings"
+)
+
+// kindHostCandidate is a KindHost suitable for a Kind cluster together with its current usage
+type kindHostCandidate struct {
+	host     *v1alpha1.KindHost
+	clusters int
+	nodes    int
+}
+
+// scheduleKindCluster selects the KindHost the Kind cluster defined by the resolved spec is created on
+// KindHosts are filtered by the host selector, availability, capacity and reported free resources
+// and the remaining ones are ordered by the number of Kind clusters, the free memory and the name
+// The returned message explains the decision, nil host is returned in case no KindHost is suitable
+func scheduleKindCluster(kindCluster *v1alpha1.KindCluster, spec v1alpha1.KindClusterSpec, hosts []v1alpha1.KindHost, kindClusters []v1alpha1.KindCluster) (*v1alpha1.KindHost, string, error) {
+	scheduling := spec.Scheduling
+	if scheduling == nil {
+		scheduling = &v1alpha1.KindClusterScheduling{}
+	}
+	selector := labels.Everything()
+	if scheduling.HostSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(scheduling.HostSelector); err != nil {
+			return nil, "", err
+		}
+	}
+	var failedHosts []string
+	if kindCluster.Status.Scheduling != nil {
+		failedHosts = kindCluster.Status.Scheduling.FailedHosts
+	}
+
+	nodes := spec.NodeCount()
+	var candidates []kindHostCandidate
+	var rejections []string
+	for i := range hosts {
+		host := &hosts[i]
+		clusterCount, nodeCount := hostUsage(kindClusters, host.Name, kindCluster.UID)
+		if reason := rejectKindHost(host, selector, failedHosts, scheduling.NodeResources, clusterCount+1, nodeCount+nodes, nodes); reason != "" {
+			rejections = append(rejections, fmt.Sprintf("%s: %s", host.Name, reason))
+			continue
+		}
+		candidates = append(candidates, kindHostCandidate{host: host, clusters: clusterCount, nodes: nodeCount})
+	}
+
+	if len(candidates) == 0 {
+		if len(rejections) == 0 {
+			return nil, "no KindHost exists", nil
+		}
+		return nil, fmt.Sprintf("no KindHost is suitable (%s)", strings.Join(rejections, "; ")), nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].clusters != candidates[j].clusters {
+			return candidates[i].clusters < candidates[j].clusters
+		}
+		memoryI, memoryJ := freeMemory(candidates[i].host), freeMemory(candidates[j].host)
+		if c := memoryI.Cmp(memoryJ); c != 0 {
+			return c > 0
+		}
+		return candidates[i].host.Name < candidates[j].host.Name
+	})
+	selected := candidates[0]
+	return selected.host, fmt.Sprintf("KindHost %s selected out of %d suitable KindHosts, it has %d Kind clusters",
+		selected.host.Name, len(candidates), selected.clusters), nil
+}
+
+// rejectKindHost returns the reason why the KindHost is not suitable for a Kind cluster, empty string if it is suitable
+// The clusters and nodes are the usage of the KindHost including the Kind cluster being scheduled
+func rejectKindHost(host *v1alpha1.KindHost, selector labels.Selector, failedHosts []string, nodeResources corev1.ResourceList, clusters, nodes, clusterNodes int) string {
+	if !selector.Matches(labels.Set(host.Labels)) {
+		return "not matched by the host selector"
+	}
+	for _, failedHost := range failedHosts {
+		if failedHost == host.Name {
+			return "the Kind cluster failed on it before"
+		}
+	}
+	if !host.DeletionTimestamp.IsZero() {
+		return "being deleted"
+	}
+	if conditions.IsFalse(host, v1alpha1.KindHostAvailableCondition) {
+		return "not available"
+	}
+	if !host.HasCapacityFor(clusters, nodes) {
+		return "no capacity left"
+	}
+	if host.Status.Resources == nil || len(nodeResources) == 0 {
+		return ""
+	}
+	reported := map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceCPU:              host.Status.Resources.CPU.Free,
+		corev1.ResourceMemory:           host.Status.Resources.Memory.Free,
+		corev1.ResourceEphemeralStorage: host.Status.Resources.Disk.Free,
+	}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage} {
+		perNode, ok := nodeResources[name]
+		if !ok {
+			continue
+		}
+		required := resource.NewMilliQuantity(perNode.MilliValue()*int64(clusterNodes), perNode.Format)
+		if free := reported[name]; free.Cmp(*required) < 0 {
+			return fmt.Sprintf("insufficient %s, %s free but %s required", name, free.String(), required.String())
+		}
+	}
+	return ""
+}
+
+// hostUsage counts the Kind clusters created on the KindHost and their nodes, the Kind cluster with the excluded UID is skipped
+// Kind clusters scheduled onto the KindHost but not created yet are counted as well, so that concurrent schedules cannot overcommit it
+func hostUsage(kindClusters []v1alpha1.KindCluster, hostName string, excluded types.UID) (int, int) {
+	clusters, nodes := 0, 0
+	for _, existing := range kindClusters {
+		if usedHost, ok := usedHostName(&existing); existing.UID == excluded || !ok || usedHost != hostName {
+			continue
+		}
+		clusters++
+		nodes += existing.Spec.NodeCount()
+	}
+	return clusters, nodes
+}
+
+// usedHostName returns the name of the KindHost whose capacity the Kind cluster uses, false in case it uses none
+// A Kind cluster uses the capacity of the KindHost it was created on or the KindHost selected by the scheduler until it gets created
+func usedHostName(kindCluster *v1alpha1.KindCluster) (string, bool) {
+	if kindCluster.Status.AppliedSpecHash != "" {
+		return kindCluster.Status.Host, true
+	}
+	if scheduling := kindCluster.Status.Scheduling; kindCluster.Spec.HostRef == nil && scheduling != nil && scheduling.Host != "" {
+		return scheduling.Host, true
+	}
+	return "", false
+}
+
+// freeMemory returns the free memory reported by the KindHost, zero in case nothing has been reported yet
+func freeMemory(host *v1alpha1.KindHost) resource.Quantity {
+	if host.Status.Resources == nil {
+		return resource.Quantity{}
+	}
+	return host.Status.Resources.Memory.Free
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"cluster-api-provider-kind/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+var _ = Describe("KindHost scheduler", func() {
+
+	var kindCluster *v1alpha1.KindCluster
+	var spec v1alpha1.KindClusterSpec
+
+	newHost := func(name string, hostLabels map[string]string, freeMemory string) v1alpha1.KindHost {
+		host := v1alpha1.KindHost{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: hostLabels}}
+		if freeMemory != "" {
+			host.Status.Resources = &v1alpha1.KindHostResources{
+				CPU:    v1alpha1.KindHostResource{Total: resource.MustParse("8"), Free: resource.MustParse("4")},
+				Memory: v1alpha1.KindHostResource{Total: resource.MustParse("16Gi"), Free: resource.MustParse(freeMemory)},
+				Disk:   v1alpha1.KindHostResource{Total: resource.MustParse("100Gi"), Free: resource.MustParse("50Gi")},
+			}
+		}
+		return host
+	}
+
+	existingCluster := func(uid, host string) v1alpha1.KindCluster {
+		existing := v1alpha1.KindCluster{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid)}}
+		existing.Status.AppliedSpecHash = "hash"
+		existing.Status.Host = host
+		return existing
+	}
+
+	BeforeEach(func() {
+		kindCluster = &v1alpha1.KindCluster{ObjectMeta: metav1.ObjectMeta{UID: "scheduled"}}
+		spec = v1alpha1.KindClusterSpec{Nodes: []v1alpha1.KindClusterNode{{Role: "control-plane"}, {Role: "worker"}}}
+	})
+
+	It("should prefer hosts with fewer clusters and more free memory", func() {
+		hosts := []v1alpha1.KindHost{newHost("lab-a", nil, "4Gi"), newHost("lab-b", nil, "8Gi"), newHost("lab-c", nil, "8Gi")}
+		kindClusters := []v1alpha1.KindCluster{existingCluster("other", "lab-b"), existingCluster("scheduled", "lab-a")}
+
+		host, message, err := scheduleKindCluster(kindCluster, spec, hosts, kindClusters)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(host.Name).To(Equal("lab-c"))
+		Expect(message).To(ContainSubstring("out of 3 suitable KindHosts"))
+
+		hosts = hosts[:2]
+		host, _, err = scheduleKindCluster(kindCluster, spec, hosts, kindClusters)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(host.Name).To(Equal("lab-a"))
+	})
+
+	It("should skip hosts not matched by the host selector", func() {
+		hosts := []v1alpha1.KindHost{newHost("lab-a", map[string]string{"zone": "a"}, ""), newHost("lab-b", map[string]string{"zone": "b"}, "")}
+		spec.Scheduling = &v1alpha1.KindClusterScheduling{HostSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "b"}}}
+
+		host, _, err := scheduleKindCluster(kindCluster, spec, hosts, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(host.Name).To(Equal("lab-b"))
+
+		spec.Scheduling.HostSelector.MatchLabels["zone"] = "c"
+		host, message, err := scheduleKindCluster(kindCluster, spec, hosts, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(host).To(BeNil())
+		Expect(message).To(ContainSubstring("lab-a: not matched by the host selector"))
+
+		spec.Scheduling.HostSelector.MatchExpressions = []metav1.LabelSelectorRequirement{{Key: "zone", Operator: "Invalid"}}
+		_, _, err = scheduleKindCluster(kindCluster, spec, hosts, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should skip unavailable, full and previously failed hosts", func() {
+		maxClusters := int32(1)
+		unavailable := newHost("lab-a", nil, "")
+		conditions.MarkFalse(&unavailable, v1alpha1.KindHostAvailableCondition, v1alpha1.KindHostUnreachableReason, clusterv1.ConditionSeverityWarning, "")
+		full := newHost("lab-b", nil, "")
+		full.Spec.Capacity.MaxClusters = &maxClusters
+		failed := newHost("lab-c", nil, "")
+		kindCluster.Status.Scheduling = &v1alpha1.KindClusterSchedulingStatus{FailedHosts: []string{"lab-c"}}
+
+		host, message, err := scheduleKindCluster(kindCluster, spec, []v1alpha1.KindHost{unavailable, full, failed}, []v1alpha1.KindCluster{existingCluster("other", "lab-b")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(host).To(BeNil())
+		Expect(message).To(ContainSubstring("lab-a: not available"))
+		Expect(message).To(ContainSubstring("lab-b: no capacity left"))
+		Expect(message).To(ContainSubstring("lab-c: the Kind cluster failed on it before"))
+	})
+
+	It("should skip hosts without enough free resources for all nodes", func() {
+		hosts := []v1alpha1.KindHost{newHost("lab-a", nil, "3Gi"), newHost("lab-b", nil, "")}
+		spec.Scheduling = &v1alpha1.KindClusterScheduling{NodeResources: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		}}
+
+		host, message, err := scheduleKindCluster(kindCluster, spec, hosts[:1], nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(host).To(BeNil())
+		Expect(message).To(ContainSubstring("insufficient memory, 3Gi free but 4Gi required"))
+
+		// Hosts which have not reported their resources yet are not filtered by resources
+		host, _, err = scheduleKindCluster(kindCluster, spec, hosts, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(host.Name).To(Equal("lab-b"))
+
+		spec.Scheduling.NodeResources[corev1.ResourceMemory] = resource.MustParse("1Gi")
+		host, _, err = scheduleKindCluster(kindCluster, spec, hosts[:1], nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(host.Name).To(Equal("lab-a"))
+	})
+
+	It("should count Kind clusters scheduled but not created yet", func() {
+		maxClusters := int32(1)
+		hosts := []v1alpha1.KindHost{newHost("lab-a", nil, "")}
+		hosts[0].Spec.Capacity.MaxClusters = &maxClusters
+		scheduled := v1alpha1.KindCluster{ObjectMeta: metav1.ObjectMeta{UID: "other"}}
+		scheduled.Status.Scheduling = &v1alpha1.KindClusterSchedulingStatus{Host: "lab-a"}
+
+		host, message, err := scheduleKindCluster(kindCluster, spec, hosts, []v1alpha1.KindCluster{scheduled})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(host).To(BeNil())
+		Expect(message).To(ContainSubstring("lab-a: no capacity left"))
+
+		// The KindCluster being scheduled does not count against its own selection
+		kindCluster.Status.Scheduling = &v1alpha1.KindClusterSchedulingStatus{Host: "lab-a"}
+		host, _, err = scheduleKindCluster(kindCluster, spec, hosts, []v1alpha1.KindCluster{*kindCluster})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(host.Name).To(Equal("lab-a"))
+	})
+
+	It("should not schedule Kind clusters provisioned before the scheduler existed", func() {
+		Expect(needsScheduling(kindCluster)).To(BeTrue())
+
+		kindCluster.Status.State = v1alpha1.KindClusterStateRunning
+		Expect(needsScheduling(kindCluster)).To(BeFalse())
+
+		// Rescheduled Kind clusters have been scheduled before
+		kindCluster.Status.State = v1alpha1.KindClusterStatePending
+		kindCluster.Status.Scheduling = &v1alpha1.KindClusterSchedulingStatus{FailedHosts: []string{"lab-a"}}
+		Expect(needsScheduling(kindCluster)).To(BeTrue())
+
+		kindCluster.Status.AppliedSpecHash = "hash"
+		Expect(needsScheduling(kindCluster)).To(BeFalse())
+	})
+})
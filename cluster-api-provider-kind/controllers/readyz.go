@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"cluster-api-provider-kind/api/v1alpha1"
+	"fmt"
+	"net/http"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// NewKindAPIReadyzCheck creates a readiness check of the Kind Wrapper APIs the Kind clusters are created on
+// The default Kind Wrapper API is pinged in case no KindHost exists, otherwise at least one KindHost has to be available
+// Only KindHosts with the watch filter label are checked in case the watch filter is set
+func NewKindAPIReadyzCheck(kindClient *KindClient, reader client.Reader, watchFilterValue string) healthz.Checker {
+	return func(req *http.Request) error {
+		var hosts v1alpha1.KindHostList
+		if err := reader.List(req.Context(), &hosts, watchFilterListOptions(watchFilterValue)...); err != nil {
+			return err
+		}
+		if len(hosts.Items) == 0 {
+			return kindClient.Ping(req.Context())
+		}
+		for i := range hosts.Items {
+			if conditions.IsTrue(&hosts.Items[i], v1alpha1.KindHostAvailableCondition) {
+				return nil
+			}
+		}
+		return fmt.Errorf("none of %d KindHosts is available", len(hosts.Items))
+	}
+}
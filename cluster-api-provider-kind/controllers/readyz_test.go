@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"cluster-api-provider-kind/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"net/http"
+	"net/http/httptest"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Kind Wrapper API readiness check", func() {
+
+	var kindClient *KindClient
+	var request *http.Request
+
+	BeforeEach(func() {
+		kindClient = newKindClient(mockKindApiServer.server.URL, http.DefaultClient, "")
+		request = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	})
+
+	It("should ping the default Kind Wrapper API when no KindHost exists", func() {
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		Expect(NewKindAPIReadyzCheck(kindClient, reader, "")(request)).To(Succeed())
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		unreachableClient := newKindClient(server.URL, http.DefaultClient, "")
+		Expect(NewKindAPIReadyzCheck(unreachableClient, reader, "")(request)).NotTo(Succeed())
+	})
+
+	It("should require an available KindHost when KindHosts exist", func() {
+		unavailable := &v1alpha1.KindHost{ObjectMeta: metav1.ObjectMeta{Name: "lab-unavailable"}}
+		conditions.MarkFalse(unavailable, v1alpha1.KindHostAvailableCondition, v1alpha1.KindHostUnreachableReason, clusterv1.ConditionSeverityWarning, "")
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(unavailable).Build()
+		Expect(NewKindAPIReadyzCheck(kindClient, reader, "")(request)).To(MatchError(ContainSubstring("none of 1 KindHosts is available")))
+
+		available := &v1alpha1.KindHost{ObjectMeta: metav1.ObjectMeta{Name: "lab-available"}}
+		conditions.MarkTrue(available, v1alpha1.KindHostAvailableCondition)
+		reader = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(unavailable, available).Build()
+		Expect(NewKindAPIReadyzCheck(kindClient, reader, "")(request)).To(Succeed())
+	})
+
+	It("should check only the KindHosts with the watch filter label when the watch filter is set", func() {
+		unavailable := &v1alpha1.KindHost{ObjectMeta: metav1.ObjectMeta{Name: "lab-team-a", Labels: map[string]string{clusterv1.WatchLabel: "team-a"}}}
+		conditions.MarkFalse(unavailable, v1alpha1.KindHostAvailableCondition, v1alpha1.KindHostUnreachableReason, clusterv1.ConditionSeverityWarning, "")
+		available := &v1alpha1.KindHost{ObjectMeta: metav1.ObjectMeta{Name: "lab-team-b", Labels: map[string]string{clusterv1.WatchLabel: "team-b"}}}
+		conditions.MarkTrue(available, v1alpha1.KindHostAvailableCondition)
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(unavailable, available).Build()
+		Expect(NewKindAPIReadyzCheck(kindClient, reader, "team-a")(request)).To(MatchError(ContainSubstring("none of 1 KindHosts is available")))
+		Expect(NewKindAPIReadyzCheck(kindClient, reader, "team-b")(request)).To(Succeed())
+	})
+})
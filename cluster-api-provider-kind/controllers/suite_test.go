@@ -19,8 +19,10 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os/exec"
 	"path/filepath"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"strings"
@@ -30,6 +32,7 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/envtest/printer"
@@ -58,20 +61,33 @@ type MockKindApiServer struct {
 	defaultCreateResponse MockKindApiServerResponse
 	defaultDeleteResponse MockKindApiServerResponse
 	defaultStatusResponse MockKindApiServerResponse
+	createPayloads        []string
+	deletePaths           []string
+	hostResponse          MockKindApiServerResponse
+	detailsResponse       MockKindApiServerResponse
 }
 
 var SimpleSuccessMockApiResponse = MockKindApiServerResponse{Status: http.StatusOK, Payload: "OK"}
 var PendingStatusMockApiResponse = MockKindApiServerResponse{Status: http.StatusOK, Payload: "{\"state\":\"pending\"}"}
 var RunningStatusMockApiResponse = MockKindApiServerResponse{Status: http.StatusOK, Payload: "{\"state\":\"running\",\"host\":\"127.0.0.1\",\"port\":6443}"}
+var FailedStatusMockApiResponse = MockKindApiServerResponse{Status: http.StatusOK, Payload: "{\"state\":\"failed\",\"reason\":\"CreateFailed\",\"message\":\"node image not found\"}"}
+var BadRequestMockApiResponse = MockKindApiServerResponse{Status: http.StatusBadRequest, Payload: "Bad request!\nFailed to parse request payload"}
 var NotFoundMockApiResponse = MockKindApiServerResponse{Status: http.StatusNotFound, Payload: "Not Found"}
 var InternalServerErrorResponse = MockKindApiServerResponse{Status: http.StatusInternalServerError, Payload: "Internal Server Error"}
+var CapabilitiesMockApiResponse = MockKindApiServerResponse{Status: http.StatusOK, Payload: "{\"apiVersion\":\"v1\",\"kindVersion\":\"0.18.0\",\"providers\":[\"docker\"],\"features\":[\"rawConfig\",\"failureDetails\",\"hostInfo\"]}"}
+var DetailsMockApiResponse = MockKindApiServerResponse{Status: http.StatusOK, Payload: "{\"name\":\"hand-made\",\"state\":\"running\",\"host\":\"127.0.0.1\",\"port\":6443,\"nodes\":[{\"name\":\"hand-made-control-plane\",\"role\":\"control-plane\",\"image\":\"kindest/node:v1.26.3\"},{\"name\":\"hand-made-worker\",\"role\":\"worker\",\"image\":\"kindest/node:v1.26.3\"}],\"kubeconfig\":\"apiVersion: v1\\nkind: Config\\n\"}"}
+var HostStatsMockApiResponse = MockKindApiServerResponse{Status: http.StatusOK, Payload: "{\"cpus\":8,\"runtimeVersion\":\"20.10.17\",\"kindVersion\":\"0.18.0\",\"clusters\":2,\"nodes\":3,\"cpu\":{\"total\":8000,\"free\":6000},\"memory\":{\"total\":17179869184,\"free\":8589934592},\"disk\":{\"total\":107374182400,\"free\":53687091200}}"}
 
 func (m *MockKindApiServer) Init() {
 	m.defaultCreateResponse = SimpleSuccessMockApiResponse
 	m.defaultDeleteResponse = SimpleSuccessMockApiResponse
 	m.defaultStatusResponse = NotFoundMockApiResponse
+	m.hostResponse = HostStatsMockApiResponse
+	m.detailsResponse = NotFoundMockApiResponse
 	m.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, kindApiPathCluster) {
+			payload, _ := io.ReadAll(r.Body)
+			m.createPayloads = append(m.createPayloads, string(payload))
 			if len(m.createResponses) > 0 {
 				response := m.createResponses[0]
 				m.createResponses = m.createResponses[1:]
@@ -80,6 +96,7 @@ func (m *MockKindApiServer) Init() {
 				m.writeResponse(w, m.defaultCreateResponse)
 			}
 		} else if r.Method == http.MethodDelete {
+			m.deletePaths = append(m.deletePaths, r.URL.Path)
 			if len(m.deleteResponses) > 0 {
 				response := m.deleteResponses[0]
 				m.deleteResponses = m.deleteResponses[1:]
@@ -87,6 +104,14 @@ func (m *MockKindApiServer) Init() {
 			} else {
 				m.writeResponse(w, m.defaultDeleteResponse)
 			}
+		} else if r.Method == http.MethodGet && r.URL.Path == kindApiPathHealth {
+			m.writeResponse(w, SimpleSuccessMockApiResponse)
+		} else if r.Method == http.MethodGet && r.URL.Path == kindApiPathHost {
+			m.writeResponse(w, m.hostResponse)
+		} else if r.Method == http.MethodGet && r.URL.Path == kindApiPathCapabilities {
+			m.writeResponse(w, CapabilitiesMockApiResponse)
+		} else if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, kindApiPathDetails) {
+			m.writeResponse(w, m.detailsResponse)
 		} else if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, kindApiPathCluster) {
 			if len(m.statusResponses) > 0 {
 				response := m.statusResponses[0]
@@ -105,9 +130,12 @@ func (m *MockKindApiServer) Reset() {
 	m.createResponses = []MockKindApiServerResponse{}
 	m.deleteResponses = []MockKindApiServerResponse{}
 	m.statusResponses = []MockKindApiServerResponse{}
+	m.createPayloads = []string{}
+	m.deletePaths = []string{}
 	m.defaultCreateResponse = SimpleSuccessMockApiResponse
 	m.defaultDeleteResponse = SimpleSuccessMockApiResponse
 	m.defaultStatusResponse = NotFoundMockApiResponse
+	m.detailsResponse = NotFoundMockApiResponse
 }
 
 func (m *MockKindApiServer) AddCreateResponse(response MockKindApiServerResponse) {
@@ -134,6 +162,22 @@ func (m *MockKindApiServer) SetDefaultStatusResponse(response MockKindApiServerR
 	m.defaultStatusResponse = response
 }
 
+func (m *MockKindApiServer) SetHostResponse(response MockKindApiServerResponse) {
+	m.hostResponse = response
+}
+
+func (m *MockKindApiServer) SetDetailsResponse(response MockKindApiServerResponse) {
+	m.detailsResponse = response
+}
+
+func (m *MockKindApiServer) CreatePayloads() []string {
+	return m.createPayloads
+}
+
+func (m *MockKindApiServer) DeletePaths() []string {
+	return m.deletePaths
+}
+
 func (m *MockKindApiServer) writeResponse(w http.ResponseWriter, response MockKindApiServerResponse) {
 	w.WriteHeader(response.Status)
 	if _, err := fmt.Fprint(w, response.Payload); err != nil {
@@ -166,12 +210,17 @@ var _ = BeforeSuite(func() {
 	ctx, cancel = context.WithCancel(context.TODO())
 
 	By("bootstrapping test environment")
+	capiDir, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "sigs.k8s.io/cluster-api").Output()
+	Expect(err).NotTo(HaveOccurred())
+
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "config", "crd", "bases"),
+			filepath.Join(strings.TrimSpace(string(capiDir)), "config", "crd", "bases"),
+		},
 		ErrorIfCRDPathMissing: true,
 	}
 
-	var err error
 	// cfg is defined in this file globally.
 	cfg, err = testEnv.Start()
 	Expect(err).NotTo(HaveOccurred())
@@ -180,6 +229,9 @@ var _ = BeforeSuite(func() {
 	err = infrastructurev1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = clusterv1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	//+kubebuilder:scaffold:scheme
 
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
@@ -191,12 +243,21 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	kindClient := &KindClient{mockKindApiServer.server.URL, http.DefaultClient}
+	kindClient := newKindClient(mockKindApiServer.server.URL, http.DefaultClient, "")
 
 	err = (&KindClusterReconciler{
-		Client:     k8sManager.GetClient(),
-		KindClient: kindClient,
-		Scheme:     k8sManager.GetScheme(),
+		Client:               k8sManager.GetClient(),
+		KindClient:           kindClient,
+		Recorder:             k8sManager.GetEventRecorderFor("kindcluster-controller"),
+		Scheme:               k8sManager.GetScheme(),
+		CredentialsNamespace: "default",
+	}).SetupWithManager(k8sManager)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = (&KindHostReconciler{
+		Client:               k8sManager.GetClient(),
+		Scheme:               k8sManager.GetScheme(),
+		CredentialsNamespace: "default",
 	}).SetupWithManager(k8sManager)
 	Expect(err).NotTo(HaveOccurred())
 
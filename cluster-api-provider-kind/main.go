@@ -17,18 +17,24 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
 	clusterapi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -55,11 +61,35 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var checkInterval time.Duration
+	var watchNamespace string
+	var watchFilterValue string
+	var maxConcurrentReconciles int
+	var orphanGCInterval time.Duration
+	var orphanGCGracePeriod time.Duration
+	var managerID string
+	var credentialsNamespace string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&checkInterval, "kind-cluster-check-interval", time.Minute,
+		"The interval of checks whether running Kind clusters still exist.")
+	flag.StringVar(&watchNamespace, "watch-namespace", "",
+		"The namespace the KindClusters are watched in, all namespaces are watched if not set.")
+	flag.StringVar(&watchFilterValue, "watch-filter", "",
+		fmt.Sprintf("The value of the %s label of the KindClusters, Clusters and KindHosts to reconcile, all of them are reconciled if not set.", clusterapi.WatchLabel))
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of KindClusters and KindHosts reconciled concurrently.")
+	flag.DurationVar(&orphanGCInterval, "orphan-gc-interval", 10*time.Minute,
+		"The interval of scans for orphaned Kind clusters, whose KindCluster does not exist anymore. Set to 0 to disable the scans.")
+	flag.DurationVar(&orphanGCGracePeriod, "orphan-gc-grace-period", 0,
+		"The time orphaned Kind clusters are kept before they get deleted, orphans are only reported if set to 0.")
+	flag.StringVar(&managerID, "manager-id", "",
+		"The identity of this management cluster recorded with the owners of Kind clusters, the UID of the kube-system namespace is used if not set.")
+	flag.StringVar(&credentialsNamespace, "credentials-namespace", os.Getenv("POD_NAMESPACE"),
+		"The namespace the credentials Secrets of the KindHosts are read from, the namespace of the manager (POD_NAMESPACE) if not set.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -75,20 +105,79 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "bbf8756e.cluster.x-k8s.io",
+		Namespace:              watchNamespace,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
+	if watchNamespace != "" {
+		setupLog.Info("watching KindClusters in a single namespace", "namespace", watchNamespace)
+	}
+
+	// The identity tells the Kind clusters of management clusters sharing a Kind Wrapper API apart
+	if managerID == "" {
+		var kubeSystem corev1.Namespace
+		if err = mgr.GetAPIReader().Get(context.Background(), client.ObjectKey{Name: metav1.NamespaceSystem}, &kubeSystem); err != nil {
+			setupLog.Error(err, "unable to get the identity of the management cluster")
+			os.Exit(1)
+		}
+		managerID = string(kubeSystem.UID)
+	}
+	setupLog.Info("recording Kind cluster owners", "manager", managerID)
 
+	kindClient := controllers.NewKindClient()
 	if err = (&controllers.KindClusterReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		KindClient: controllers.NewKindClient(),
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		KindClient:              kindClient,
+		Recorder:                mgr.GetEventRecorderFor("kindcluster-controller"),
+		CheckInterval:           checkInterval,
+		WatchFilterValue:        watchFilterValue,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		ManagerID:               managerID,
+		CredentialsNamespace:    credentialsNamespace,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KindCluster")
 		os.Exit(1)
 	}
+	if err = controllers.RegisterKindClusterCollector(mgr.GetClient(), watchFilterValue); err != nil {
+		setupLog.Error(err, "unable to register metrics", "controller", "KindCluster")
+		os.Exit(1)
+	}
+	if err = (&controllers.KindHostReconciler{
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		WatchFilterValue:        watchFilterValue,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		CredentialsNamespace:    credentialsNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KindHost")
+		os.Exit(1)
+	}
+	// Kind clusters of KindClusters not watched by this manager would be mistaken for orphans
+	if watchNamespace != "" || watchFilterValue != "" {
+		setupLog.Info("orphaned Kind cluster garbage collection is disabled with a watch namespace or filter")
+	} else if orphanGCInterval > 0 {
+		if err = (&controllers.KindClusterGarbageCollector{
+			Client:               mgr.GetClient(),
+			KindClient:           kindClient,
+			Recorder:             mgr.GetEventRecorderFor("kindcluster-gc"),
+			Interval:             orphanGCInterval,
+			GracePeriod:          orphanGCGracePeriod,
+			ManagerID:            managerID,
+			CredentialsNamespace: credentialsNamespace,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up garbage collector", "controller", "KindCluster")
+			os.Exit(1)
+		}
+	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&infrastructurev1alpha1.KindCluster{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "KindCluster")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -99,6 +188,10 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("kind-api", controllers.NewKindAPIReadyzCheck(kindClient, mgr.GetClient(), watchFilterValue)); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -1,51 +1,171 @@
 package api
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/julienschmidt/httprouter"
-	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/labels"
+	"kind-wrapper-api/health"
+	"kind-wrapper-api/kind"
 	"kind-wrapper-api/service"
+	"kind-wrapper-api/system"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
+const apiVersion = "v1"
+
+// Features of the API listed by the capabilities endpoint
+const (
+	FeatureRawConfig = "rawConfig"
+	FeatureFailureDetails = "failureDetails"
+	FeatureHostInfo = "hostInfo"
+	FeatureClusterDetails = "clusterDetails"
+	FeatureBearerToken = "bearerToken"
+	FeatureTLS = "tls"
+	FeatureOwnership = "ownership"
+	FeatureClusterLabels = "clusterLabels"
+	FeatureClusterFilters = "clusterFilters"
+	FeatureClusterTTL = "clusterTTL"
+)
+
+const (
+	// ownerParam is the query parameter with the owner of a cluster
+	ownerParam = "owner"
+	// managerParam is the query parameter with the identity of the management cluster of the owner of a cluster
+	managerParam = "manager"
+	// labelParam is the repeated query parameter with the labels of the owner of a cluster in the key=value format
+	labelParam = "label"
+	// ttlParam is the query parameter with the time a cluster is kept until it gets deleted, e.g. 24h
+	ttlParam = "ttl"
+
+	// The query parameters filtering and paginating the listed clusters
+	labelSelectorParam = "labelSelector"
+	stateParam = "state"
+	olderThanParam = "olderThan"
+	limitParam = "limit"
+	continueParam = "continue"
+	// continueHeader is the response header with the continue token of the next page of listed clusters
+	continueHeader = "X-Continue"
+)
+
+// HostInfo is the response of the host endpoint, it describes the resources, versions and usage of the host
+type HostInfo struct {
+	system.Stats
+	service.KindInfo
+}
+
+// Capabilities is the response of the capabilities endpoint, it lists the supported Kind Providers and API features
+type Capabilities struct {
+	APIVersion string `json:"apiVersion"`
+	KindVersion string `json:"kindVersion"`
+	Providers []string `json:"providers"`
+	Features []string `json:"features"`
+}
+
 // API implements HTTP server and routing of incoming requests
 type API struct {
 	host string
 	port int
 	kindService *service.KindService
+	statsProvider system.Provider
+	token string
+	tlsCertFile string
+	tlsKeyFile string
+	readinessChecks []health.Check
 }
 
-// NewAPI creates a new instance of API with the specified host, port and service (as a source of data)
-func NewAPI(host string, port int, kindService *service.KindService) *API {
-	return &API{host: host, port: port, kindService: kindService}
+// NewAPI creates a new instance of API with the specified host, port and service and stats provider (as sources of data)
+func NewAPI(host string, port int, kindService *service.KindService, statsProvider system.Provider) *API {
+	return &API{host: host, port: port, kindService: kindService, statsProvider: statsProvider}
+}
+
+// WithToken requires all cluster requests to be authorized by the specified bearer token
+func (api *API) WithToken(token string) *API {
+	api.token = token
+	return api
+}
+
+// WithTLS makes the server accept HTTPS connections with the specified certificate and key
+func (api *API) WithTLS(certFile, keyFile string) *API {
+	api.tlsCertFile = certFile
+	api.tlsKeyFile = keyFile
+	return api
+}
+
+// WithReadinessChecks makes the readiness endpoint report the results of the specified checks
+func (api *API) WithReadinessChecks(checks ...health.Check) *API {
+	api.readinessChecks = checks
+	return api
 }
 
 // Start binds all available routes and starts the server
 func (api *API) Start() error {
 	addr := fmt.Sprintf("%s:%d", api.host, api.port)
+	log.Printf("Listening on %s", addr)
+	if api.tlsCertFile != "" {
+		return http.ListenAndServeTLS(addr, api.tlsCertFile, api.tlsKeyFile, api.router())
+	}
+	return http.ListenAndServe(addr, api.router())
+}
+
+func (api *API) router() http.Handler {
 	router := &httprouter.Router{
 		NotFound: http.NotFoundHandler(),
 	}
-	router.GET("/health", api.handleHealth)
-	router.GET("/api/v1/cluster/:name", api.handleGetClusterStatus)
-	router.POST("/api/v1/cluster", api.handleCreateClusterAsync)
-	router.DELETE("/api/v1/cluster/:name", api.handleDeleteClusterAsync)
-	log.Printf("Listening on %s", addr)
-	return http.ListenAndServe(addr, router)
+	router.GET("/health", api.handleLiveness)
+	router.GET("/livez", api.handleLiveness)
+	router.GET("/readyz", api.handleReadiness)
+	router.GET("/api/v1/host", api.authorize(api.handleGetHost))
+	router.GET("/api/v1/capabilities", api.authorize(api.handleGetCapabilities))
+	router.GET("/api/v1/clusters", api.authorize(api.handleListClusters))
+	router.GET("/api/v1/cluster/:name", api.authorize(api.handleGetClusterStatus))
+	router.GET("/api/v1/cluster/:name/details", api.authorize(api.handleGetClusterDetails))
+	router.POST("/api/v1/cluster", api.authorize(api.handleCreateClusterAsync))
+	router.PUT("/api/v1/cluster/:name/owner", api.authorize(api.handleSetClusterOwner))
+	router.PUT("/api/v1/cluster/:name/ttl", api.authorize(api.handleSetClusterTTL))
+	router.DELETE("/api/v1/cluster/:name", api.authorize(api.handleDeleteClusterAsync))
+	return router
+}
+
+// authorize rejects requests without the configured bearer token, all requests are accepted if no token is configured
+func (api *API) authorize(handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		expected := []byte("Bearer " + api.token)
+		actual := []byte(req.Header.Get("Authorization"))
+		if api.token != "" && subtle.ConstantTimeCompare(expected, actual) != 1 {
+			writeResponse(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		handle(w, req, params)
+	}
 }
 
+// handleCreateClusterAsync creates a cluster, the optional owner, manager and label query parameters record the owner of the cluster
+// The optional ttl query parameter makes the reaper delete the cluster once the TTL passes
 func (api *API) handleCreateClusterAsync(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	var clusterConfig service.ClusterConfig
-	if err := yaml.NewDecoder(req.Body).Decode(&clusterConfig); err != nil {
+	clusterConfig, err := service.ParseClusterConfig(req.Body)
+	if err != nil {
 		writeResponse(w, http.StatusBadRequest, fmt.Sprintf("Bad request!\nFailed to parse request payload: %s", err))
+	} else if owner, err := parseClusterOwner(req); err != nil {
+		writeResponse(w, http.StatusBadRequest, fmt.Sprintf("Bad request!\nFailed to parse labels: %s", err))
+	} else if ttl, err := parseClusterTTL(req.URL.Query()); err != nil {
+		writeResponse(w, http.StatusBadRequest, fmt.Sprintf("Bad request!\n%s", err))
 	} else {
-		if _, err = api.kindService.GetClusterState(clusterConfig.Name); err == nil {
+		if ttl > 0 {
+			expiresAt := time.Now().Add(ttl)
+			owner.ExpiresAt = &expiresAt
+		}
+		// Clusters whose last creation failed can be created again
+		if state, err := api.kindService.GetClusterState(clusterConfig.Name); err == nil && state.Reason != service.KindClusterReasonCreateFailed {
 			writeResponse(w, http.StatusConflict, fmt.Sprintf("Conflict!\nCluster with the same name already exists: %s", err))
-		} else if err = api.kindService.CreateCluster(clusterConfig); err != nil {
-			writeResponse(w, http.StatusInternalServerError, "Internal Server Error")
+		} else if err = api.kindService.CreateCluster(clusterConfig, owner); err != nil {
+			writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
 		} else {
 			writeResponse(w, http.StatusOK, "OK")
 		}
@@ -62,6 +182,139 @@ func (api *API) handleDeleteClusterAsync(w http.ResponseWriter, _ *http.Request,
 	}
 }
 
+// handleListClusters lists the clusters with their owners and labels, including clusters not created by the API
+// The clusters are filtered by the labelSelector, state and olderThan query parameters and paginated by the limit and continue ones,
+// the continue token of the next page is returned in the X-Continue header
+func (api *API) handleListClusters(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	options, err := parseListClustersOptions(req.URL.Query())
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, fmt.Sprintf("Bad request!\n%s", err))
+		return
+	}
+	clusters, continueToken, err := api.kindService.ListClusters(options)
+	if err != nil && errors.Is(err, service.InvalidContinueTokenError) {
+		writeResponse(w, http.StatusBadRequest, fmt.Sprintf("Bad request!\n%s", err))
+		return
+	} else if err != nil {
+		writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+		return
+	}
+	data, err := json.Marshal(clusters)
+	if err != nil {
+		writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+	} else {
+		if continueToken != "" {
+			w.Header().Set(continueHeader, continueToken)
+		}
+		writeResponse(w, http.StatusOK, string(data))
+	}
+}
+
+// handleSetClusterOwner records the owner and manager query parameters as the owner of an existing cluster, an empty owner releases the cluster
+// The labels of the owner are replaced only if any label query parameter is present
+func (api *API) handleSetClusterOwner(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	name := params.ByName("name")
+	if name == "" {
+		writeResponse(w, http.StatusBadRequest, "Bad Request!\nInvalid name provided")
+		return
+	}
+	owner, err := parseClusterOwner(req)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, fmt.Sprintf("Bad request!\nFailed to parse labels: %s", err))
+		return
+	}
+	err = api.kindService.SetClusterOwner(name, owner)
+	if err != nil && errors.Is(err, service.KindClusterNotFoundError) {
+		writeResponse(w, http.StatusNotFound, "Not Found")
+	} else if err != nil {
+		writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+	} else {
+		writeResponse(w, http.StatusOK, "OK")
+	}
+}
+
+// handleSetClusterTTL sets the ttl query parameter as the time an existing cluster is kept from now on, an empty TTL keeps the cluster until deleted
+func (api *API) handleSetClusterTTL(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	name := params.ByName("name")
+	if name == "" {
+		writeResponse(w, http.StatusBadRequest, "Bad Request!\nInvalid name provided")
+		return
+	}
+	ttl, err := parseClusterTTL(req.URL.Query())
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, fmt.Sprintf("Bad request!\n%s", err))
+		return
+	}
+	err = api.kindService.SetClusterTTL(name, ttl)
+	if err != nil && errors.Is(err, service.KindClusterNotFoundError) {
+		writeResponse(w, http.StatusNotFound, "Not Found")
+	} else if err != nil {
+		writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+	} else {
+		writeResponse(w, http.StatusOK, "OK")
+	}
+}
+
+// parseClusterTTL reads the TTL of a cluster from the query parameters, zero is returned if the TTL is not set
+func parseClusterTTL(query url.Values) (time.Duration, error) {
+	value := query.Get(ttlParam)
+	if value == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid %s %q, a positive duration is required", ttlParam, value)
+	}
+	return ttl, nil
+}
+
+// parseListClustersOptions reads the filters and the pagination of the listed clusters from the query parameters
+// The label selector uses the Kubernetes syntax and olderThan is a duration, e.g. 24h
+func parseListClustersOptions(query url.Values) (service.ListClustersOptions, error) {
+	var options service.ListClustersOptions
+	if value := query.Get(labelSelectorParam); value != "" {
+		selector, err := labels.Parse(value)
+		if err != nil {
+			return options, fmt.Errorf("invalid %s: %w", labelSelectorParam, err)
+		}
+		options.Selector = selector
+	}
+	if value := query.Get(stateParam); value != "" {
+		switch state := service.KindClusterState(value); state {
+		case service.KindClusterStatePending, service.KindClusterStateRunning, service.KindClusterStateFailed, service.KindClusterStateUnknown:
+			options.State = state
+		default:
+			return options, fmt.Errorf("invalid %s %q", stateParam, value)
+		}
+	}
+	if value := query.Get(olderThanParam); value != "" {
+		olderThan, err := time.ParseDuration(value)
+		if err != nil || olderThan <= 0 {
+			return options, fmt.Errorf("invalid %s %q, a positive duration is required", olderThanParam, value)
+		}
+		options.OlderThan = olderThan
+	}
+	if value := query.Get(limitParam); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return options, fmt.Errorf("invalid %s %q, a positive number is required", limitParam, value)
+		}
+		options.Limit = limit
+	}
+	options.Continue = query.Get(continueParam)
+	return options, nil
+}
+
+// parseClusterOwner reads the owner, its manager and its labels from the query parameters of a request
+func parseClusterOwner(req *http.Request) (service.ClusterOwner, error) {
+	query := req.URL.Query()
+	labels, err := service.ParseClusterLabels(query[labelParam])
+	if err != nil {
+		return service.ClusterOwner{}, err
+	}
+	return service.ClusterOwner{Owner: query.Get(ownerParam), Manager: query.Get(managerParam), Labels: labels}, nil
+}
+
 func (api *API) handleGetClusterStatus(w http.ResponseWriter, _ *http.Request, params httprouter.Params) {
 	name := params.ByName("name")
 	if name == "" {
@@ -83,10 +336,91 @@ func (api *API) handleGetClusterStatus(w http.ResponseWriter, _ *http.Request, p
 	}
 }
 
-func (api *API) handleHealth(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+// handleGetClusterDetails looks up a cluster by its Kind name, so that clusters not created by the API can be adopted
+func (api *API) handleGetClusterDetails(w http.ResponseWriter, _ *http.Request, params httprouter.Params) {
+	name := params.ByName("name")
+	if name == "" {
+		writeResponse(w, http.StatusBadRequest, "Bad Request!\nInvalid name provided")
+		return
+	}
+	details, err := api.kindService.GetClusterDetails(name)
+	if err != nil && errors.Is(err, service.KindClusterNotFoundError) {
+		writeResponse(w, http.StatusNotFound, "Not Found")
+	} else if err != nil {
+		writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+	} else {
+		data, err := json.Marshal(details)
+		if err != nil {
+			writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+		} else {
+			writeResponse(w, http.StatusOK, string(data))
+		}
+	}
+}
+
+func (api *API) handleGetHost(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	stats, err := api.statsProvider.Stats()
+	if err != nil {
+		writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+		return
+	}
+	kindInfo, err := api.kindService.GetKindInfo()
+	if err != nil {
+		writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+		return
+	}
+	data, err := json.Marshal(HostInfo{Stats: stats, KindInfo: kindInfo})
+	if err != nil {
+		writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+	} else {
+		writeResponse(w, http.StatusOK, string(data))
+	}
+}
+
+func (api *API) handleGetCapabilities(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	features := []string{FeatureRawConfig, FeatureFailureDetails, FeatureHostInfo, FeatureClusterDetails, FeatureOwnership, FeatureClusterLabels, FeatureClusterFilters, FeatureClusterTTL}
+	if api.token != "" {
+		features = append(features, FeatureBearerToken)
+	}
+	if api.tlsCertFile != "" {
+		features = append(features, FeatureTLS)
+	}
+	capabilities := Capabilities{
+		APIVersion: apiVersion,
+		KindVersion: api.kindService.KindVersion(),
+		Providers: kind.SupportedProviders,
+		Features: features,
+	}
+	data, err := json.Marshal(capabilities)
+	if err != nil {
+		writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+	} else {
+		writeResponse(w, http.StatusOK, string(data))
+	}
+}
+
+// handleLiveness reports that the server is running, dependencies are checked by the readiness endpoint
+func (api *API) handleLiveness(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	writeResponse(w, http.StatusOK, "OK")
 }
 
+// handleReadiness runs the readiness checks and reports their results as JSON
+// Service Unavailable status is returned in case any check fails
+func (api *API) handleReadiness(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	report := health.Run(api.readinessChecks)
+	data, err := json.Marshal(report)
+	if err != nil {
+		writeResponse(w, http.StatusInternalServerError, fmt.Sprintf("Internal Server Error!\n%s", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if report.Ready {
+		writeResponse(w, http.StatusOK, string(data))
+	} else {
+		writeResponse(w, http.StatusServiceUnavailable, string(data))
+	}
+}
+
 func writeResponse(w http.ResponseWriter, statusCode int, payload interface{}) {
 	w.WriteHeader(statusCode)
 	if _, err := fmt.Fprint(w, payload); err != nil {
@@ -0,0 +1,409 @@
+package api
+
+import (
+	"github.com/stretchr/testify/require"
+	"encoding/json"
+	"errors"
+	"kind-wrapper-api/health"
+	"kind-wrapper-api/kind"
+	"kind-wrapper-api/service"
+	"kind-wrapper-api/system"
+	"kind-wrapper-api/test"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIAuthorization(t *testing.T) {
+	kubeConfigDir, err := os.MkdirTemp(os.TempDir(), "kube")
+	require.NoError(t, err)
+
+	defer func() {
+		_ = os.RemoveAll(kubeConfigDir)
+	}()
+
+	kubeConfigPath, err := test.SetupKubeConfig(kubeConfigDir, test.EmptyKubeConfig)
+	require.NoError(t, err)
+
+	kindService := service.NewKindService(test.NewMockKindClient(), kubeConfigPath)
+	stats := system.Stats{
+		CPU:    system.Resource{Total: 8000, Free: 6500},
+		Memory: system.Resource{Total: 16 << 30, Free: 10 << 30},
+		Disk:   system.Resource{Total: 500 << 30, Free: 200 << 30},
+	}
+	statsProvider := test.NewMockStatsProvider(stats, nil)
+
+	request := func(router http.Handler, method, path, authorization string) int {
+		req := httptest.NewRequest(method, path, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	t.Run("test requests without token configured", func(t *testing.T) {
+		router := NewAPI("", 0, kindService, statsProvider).router()
+		require.Equal(t, http.StatusOK, request(router, http.MethodGet, "/health", ""))
+		require.Equal(t, http.StatusNotFound, request(router, http.MethodGet, "/api/v1/cluster/kind", ""))
+	})
+
+	t.Run("test requests with token configured", func(t *testing.T) {
+		router := NewAPI("", 0, kindService, statsProvider).WithToken("secret").router()
+		require.Equal(t, http.StatusOK, request(router, http.MethodGet, "/health", ""))
+		require.Equal(t, http.StatusUnauthorized, request(router, http.MethodGet, "/api/v1/cluster/kind", ""))
+		require.Equal(t, http.StatusUnauthorized, request(router, http.MethodGet, "/api/v1/cluster/kind", "Bearer wrong"))
+		require.Equal(t, http.StatusUnauthorized, request(router, http.MethodPost, "/api/v1/cluster", "secret"))
+		require.Equal(t, http.StatusUnauthorized, request(router, http.MethodDelete, "/api/v1/cluster/kind", ""))
+		require.Equal(t, http.StatusNotFound, request(router, http.MethodGet, "/api/v1/cluster/kind", "Bearer secret"))
+		require.Equal(t, http.StatusOK, request(router, http.MethodDelete, "/api/v1/cluster/kind", "Bearer secret"))
+	})
+}
+
+func TestAPIHost(t *testing.T) {
+	mockKindClient := test.NewMockKindClient()
+	mockKindClient.SetClusters(map[string]int{"default-kind": 1, "default-kind-2": 3})
+	kindService := service.NewKindService(mockKindClient, "")
+	stats := system.Stats{
+		CPUs:   8,
+		CPU:    system.Resource{Total: 8000, Free: 6500},
+		Memory: system.Resource{Total: 16 << 30, Free: 10 << 30},
+		Disk:   system.Resource{Total: 500 << 30, Free: 200 << 30},
+	}
+
+	t.Run("test get host stats", func(t *testing.T) {
+		router := NewAPI("", 0, kindService, test.NewMockStatsProvider(stats, nil)).router()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/host", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var received HostInfo
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+		require.Equal(t, stats, received.Stats)
+		require.Equal(t, service.KindInfo{
+			Provider: "docker",
+			RuntimeVersion: "20.10.17",
+			KindVersion: test.MockKindVersion,
+			Clusters: 2,
+			Nodes: 4,
+		}, received.KindInfo)
+		require.Contains(t, recorder.Body.String(), `"cpus":8`)
+	})
+
+	t.Run("test get host runtime failure", func(t *testing.T) {
+		failingKindClient := test.NewMockKindClient()
+		failingKindClient.SetRuntimeVersion("", errors.New("docker not running"))
+		router := NewAPI("", 0, service.NewKindService(failingKindClient, ""), test.NewMockStatsProvider(stats, nil)).router()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/host", nil))
+		require.Equal(t, http.StatusInternalServerError, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "docker not running")
+	})
+
+	t.Run("test get host stats failure", func(t *testing.T) {
+		router := NewAPI("", 0, kindService, test.NewMockStatsProvider(system.Stats{}, errors.New("failed to read stats"))).router()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/host", nil))
+		require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+func TestAPIClusterDetails(t *testing.T) {
+	kubeConfigDir, err := os.MkdirTemp(os.TempDir(), "kube")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(kubeConfigDir)
+	}()
+	kubeConfigPath, err := test.SetupKubeConfig(kubeConfigDir, test.KubeConfig)
+	require.NoError(t, err)
+
+	mockKindClient := test.NewMockKindClient()
+	mockKindClient.SetNodes("kind", []kind.Node{{Name: "kind-control-plane", Role: "control-plane", Image: "kindest/node:v1.26.3"}})
+	router := NewAPI("", 0, service.NewKindService(mockKindClient, kubeConfigPath), test.NewMockStatsProvider(system.Stats{}, nil)).router()
+
+	t.Run("test get details of a cluster by its kind name", func(t *testing.T) {
+		mockKindClient.SetDefaultHasNodes(func() (bool, error) {
+			return true, nil
+		})
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/cluster/kind/details", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var received service.KindClusterDetails
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+		require.Equal(t, "kind", received.Name)
+		require.Equal(t, service.KindClusterStateRunning, received.State)
+		require.Equal(t, 8080, received.Port)
+		require.Equal(t, []kind.Node{{Name: "kind-control-plane", Role: "control-plane", Image: "kindest/node:v1.26.3"}}, received.Nodes)
+		require.Equal(t, test.KubeConfig, received.KubeConfig)
+		require.Contains(t, recorder.Body.String(), `"state":"running"`)
+	})
+
+	t.Run("test get details of a missing cluster", func(t *testing.T) {
+		mockKindClient.SetDefaultHasNodes(func() (bool, error) {
+			return false, nil
+		})
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/cluster/missing/details", nil))
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestAPIClusterOwners(t *testing.T) {
+	mockKindClient := test.NewMockKindClient()
+	mockKindClient.SetClusters(map[string]int{"default-kind": 1, "external": 1})
+	kindService := service.NewKindService(mockKindClient, "")
+	router := NewAPI("", 0, kindService, test.NewMockStatsProvider(system.Stats{}, nil)).router()
+
+	listClusters := func() []service.KindClusterListItem {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/clusters", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var received []service.KindClusterListItem
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+		return received
+	}
+
+	t.Run("test list clusters with owners", func(t *testing.T) {
+		require.Equal(t, []service.KindClusterListItem{{Name: "default-kind"}, {Name: "external"}}, listClusters())
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/default-kind/owner?owner=uid-1", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, []service.KindClusterListItem{{Name: "default-kind", Owner: "uid-1"}, {Name: "external"}}, listClusters())
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/default-kind/owner", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, []service.KindClusterListItem{{Name: "default-kind"}, {Name: "external"}}, listClusters())
+	})
+
+	t.Run("test set owner with labels", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/default-kind/owner?owner=uid-1&label=team%3Dpayments&label=tier%3Dpreview", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		labels := map[string]string{"team": "payments", "tier": "preview"}
+		require.Equal(t, []service.KindClusterListItem{{Name: "default-kind", Owner: "uid-1", Labels: labels}, {Name: "external"}}, listClusters())
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/default-kind/owner", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, []service.KindClusterListItem{{Name: "default-kind", Labels: labels}, {Name: "external"}}, listClusters())
+	})
+
+	t.Run("test reject invalid labels", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/default-kind/owner?owner=uid-1&label=team", nil))
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		body := strings.NewReader("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nname: labeled\n")
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/cluster?owner=uid-2&label=bad%20key%3Dvalue", body))
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "invalid cluster label")
+	})
+
+	t.Run("test set owner with manager", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/external/owner?owner=uid-3&manager=mgmt-a", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Contains(t, listClusters(), service.KindClusterListItem{Name: "external", Owner: "uid-3", Manager: "mgmt-a"})
+
+		// Releasing the cluster removes the manager as well
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/external/owner", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Contains(t, listClusters(), service.KindClusterListItem{Name: "external"})
+	})
+
+	t.Run("test set owner of missing cluster", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/missing/owner?owner=uid-1", nil))
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestAPIListClusters(t *testing.T) {
+	mockKindClient := test.NewMockKindClient()
+	mockKindClient.SetClusters(map[string]int{"kind-a": 1, "kind-b": 1, "kind-c": 1})
+	kindService := service.NewKindService(mockKindClient, "")
+	require.NoError(t, kindService.SetClusterOwner("kind-a", service.ClusterOwner{Owner: "uid-1", Labels: map[string]string{"team": "payments"}}))
+	require.NoError(t, kindService.SetClusterOwner("kind-c", service.ClusterOwner{Owner: "uid-2", Labels: map[string]string{"team": "payments"}}))
+	router := NewAPI("", 0, kindService, test.NewMockStatsProvider(system.Stats{}, nil)).router()
+
+	listClusters := func(query string) (*httptest.ResponseRecorder, []string) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/clusters?"+query, nil))
+		if recorder.Code != http.StatusOK {
+			return recorder, nil
+		}
+		var received []service.KindClusterListItem
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+		var names []string
+		for _, cluster := range received {
+			names = append(names, cluster.Name)
+		}
+		return recorder, names
+	}
+
+	t.Run("test list clusters by label selector in pages", func(t *testing.T) {
+		recorder, names := listClusters("labelSelector=team%3Dpayments&limit=1")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, []string{"kind-a"}, names)
+		continueToken := recorder.Header().Get("X-Continue")
+		require.NotEmpty(t, continueToken)
+
+		recorder, names = listClusters("labelSelector=team%3Dpayments&limit=1&continue=" + continueToken)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, []string{"kind-c"}, names)
+		require.Empty(t, recorder.Header().Get("X-Continue"))
+
+		_, names = listClusters("labelSelector=team%21%3Dpayments")
+		require.Equal(t, []string{"kind-b"}, names)
+	})
+
+	t.Run("test reject invalid filters", func(t *testing.T) {
+		for _, query := range []string{"labelSelector=team%3D%3D%3D", "state=lost", "olderThan=1d", "olderThan=-1h", "limit=0", "continue=%21"} {
+			recorder, _ := listClusters(query)
+			require.Equal(t, http.StatusBadRequest, recorder.Code, query)
+		}
+	})
+}
+
+func TestAPIClusterTTL(t *testing.T) {
+	mockKindClient := test.NewMockKindClient()
+	mockKindClient.SetDefaultHasNodes(func() (bool, error) {
+		return true, nil
+	})
+	mockKindClient.SetClusters(map[string]int{"kind-a": 1})
+	kindService := service.NewKindService(mockKindClient, "")
+	router := NewAPI("", 0, kindService, test.NewMockStatsProvider(system.Stats{}, nil)).router()
+
+	expiresAt := func(name string) *time.Time {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/clusters", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var received []service.KindClusterListItem
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+		for _, cluster := range received {
+			if cluster.Name == name {
+				return cluster.ExpiresAt
+			}
+		}
+		return nil
+	}
+
+	t.Run("test create cluster with ttl", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		body := strings.NewReader("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nname: kind-b\n")
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/cluster?owner=uid-1&ttl=2h", body))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		mockKindClient.SetClusters(map[string]int{"kind-a": 1, "kind-b": 1})
+		require.WithinDuration(t, time.Now().Add(2*time.Hour), *expiresAt("kind-b"), time.Minute)
+	})
+
+	t.Run("test set and clear ttl", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/kind-a/ttl?ttl=30m", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.WithinDuration(t, time.Now().Add(30*time.Minute), *expiresAt("kind-a"), time.Minute)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/kind-a/ttl", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Nil(t, expiresAt("kind-a"))
+	})
+
+	t.Run("test reject invalid ttl", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/kind-a/ttl?ttl=1d", nil))
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		body := strings.NewReader("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nname: kind-c\n")
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/cluster?ttl=-1h", body))
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/v1/cluster/missing/ttl?ttl=1h", nil))
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestAPICapabilities(t *testing.T) {
+	kindService := service.NewKindService(test.NewMockKindClient(), "")
+	statsProvider := test.NewMockStatsProvider(system.Stats{}, nil)
+
+	t.Run("test get capabilities", func(t *testing.T) {
+		router := NewAPI("", 0, kindService, statsProvider).router()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/capabilities", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var received Capabilities
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+		require.Equal(t, "v1", received.APIVersion)
+		require.Equal(t, test.MockKindVersion, received.KindVersion)
+		require.Equal(t, []string{"docker"}, received.Providers)
+		require.Equal(t, []string{FeatureRawConfig, FeatureFailureDetails, FeatureHostInfo, FeatureClusterDetails, FeatureOwnership, FeatureClusterLabels, FeatureClusterFilters, FeatureClusterTTL}, received.Features)
+	})
+
+	t.Run("test get capabilities with security features", func(t *testing.T) {
+		router := NewAPI("", 0, kindService, statsProvider).WithToken("secret").WithTLS("cert.pem", "key.pem").router()
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/capabilities", nil)
+		request.Header.Set("Authorization", "Bearer secret")
+		router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var received Capabilities
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+		require.Contains(t, received.Features, FeatureBearerToken)
+		require.Contains(t, received.Features, FeatureTLS)
+	})
+}
+
+func TestAPIHealth(t *testing.T) {
+	kindService := service.NewKindService(test.NewMockKindClient(), "")
+	statsProvider := test.NewMockStatsProvider(system.Stats{}, nil)
+	passing := health.Check{Name: "passing", Run: func() error { return nil }}
+	failing := health.Check{Name: "failing", Run: func() error { return errors.New("docker not running") }}
+
+	t.Run("test liveness", func(t *testing.T) {
+		router := NewAPI("", 0, kindService, statsProvider).WithToken("secret").WithReadinessChecks(failing).router()
+		for _, path := range []string{"/health", "/livez"} {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+			require.Equal(t, http.StatusOK, recorder.Code)
+		}
+	})
+
+	t.Run("test readiness", func(t *testing.T) {
+		router := NewAPI("", 0, kindService, statsProvider).WithToken("secret").WithReadinessChecks(passing).router()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+		var report health.Report
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+		require.True(t, report.Ready)
+		require.Equal(t, []health.Result{{Name: "passing", Ready: true}}, report.Checks)
+	})
+
+	t.Run("test readiness failure", func(t *testing.T) {
+		router := NewAPI("", 0, kindService, statsProvider).WithReadinessChecks(passing, failing).router()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+		var report health.Report
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+		require.False(t, report.Ready)
+		require.Equal(t, health.Result{Name: "failing", Ready: false, Message: "docker not running"}, report.Checks[1])
+	})
+}
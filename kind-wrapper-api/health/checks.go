@@ -0,0 +1,79 @@
+package health
+
+import (
+	"errors"
+	"fmt"
+	"kind-wrapper-api/kind"
+	"kind-wrapper-api/system"
+	"os"
+)
+
+// Check is a named check of a dependency the API requires to create Kind clusters
+type Check struct {
+	Name string
+	Run  func() error
+}
+
+// Result contains the result of a single check
+type Result struct {
+	Name    string `json:"name"`
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
+}
+
+// Report contains the results of all checks, it is ready only if all checks are ready
+type Report struct {
+	Ready  bool     `json:"ready"`
+	Checks []Result `json:"checks"`
+}
+
+// Run runs all checks in the specified order and reports their results
+func Run(checks []Check) Report {
+	report := Report{Ready: true, Checks: []Result{}}
+	for _, check := range checks {
+		result := Result{Name: check.Name, Ready: true}
+		if err := check.Run(); err != nil {
+			result.Ready = false
+			result.Message = err.Error()
+			report.Ready = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+// RuntimeCheck checks that the container runtime running the Kind nodes is reachable
+func RuntimeCheck(kindClient kind.Client) Check {
+	return Check{Name: "runtime", Run: func() error {
+		_, err := kindClient.RuntimeVersion()
+		return err
+	}}
+}
+
+// KubeConfigCheck checks that the kubeconfig Kind writes the cluster contexts to can be read and written
+func KubeConfigCheck(kubeConfigPath string) Check {
+	return Check{Name: "kubeconfig", Run: func() error {
+		if kubeConfigPath == "" {
+			return errors.New("kubeconfig not found")
+		}
+		file, err := os.OpenFile(kubeConfigPath, os.O_RDWR, 0)
+		if err != nil {
+			return err
+		}
+		return file.Close()
+	}}
+}
+
+// DiskCheck checks that the disk Kind nodes are stored on has at least the specified number of bytes free
+func DiskCheck(statsProvider system.Provider, minFreeBytes int64) Check {
+	return Check{Name: "disk", Run: func() error {
+		stats, err := statsProvider.Stats()
+		if err != nil {
+			return err
+		}
+		if stats.Disk.Free < minFreeBytes {
+			return fmt.Errorf("%d bytes free, at least %d bytes required", stats.Disk.Free, minFreeBytes)
+		}
+		return nil
+	}}
+}
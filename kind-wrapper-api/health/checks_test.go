@@ -0,0 +1,63 @@
+package health
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"kind-wrapper-api/system"
+	"kind-wrapper-api/test"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecks(t *testing.T) {
+	t.Run("test run checks", func(t *testing.T) {
+		report := Run([]Check{
+			{Name: "first", Run: func() error { return nil }},
+			{Name: "second", Run: func() error { return errors.New("second failed") }},
+		})
+		require.False(t, report.Ready)
+		require.Equal(t, []Result{
+			{Name: "first", Ready: true},
+			{Name: "second", Ready: false, Message: "second failed"},
+		}, report.Checks)
+
+		report = Run(nil)
+		require.True(t, report.Ready)
+		require.Empty(t, report.Checks)
+	})
+
+	t.Run("test runtime check", func(t *testing.T) {
+		kindClient := test.NewMockKindClient()
+		require.NoError(t, RuntimeCheck(kindClient).Run())
+
+		kindClient.SetRuntimeVersion("", errors.New("docker not running"))
+		require.EqualError(t, RuntimeCheck(kindClient).Run(), "docker not running")
+	})
+
+	t.Run("test kubeconfig check", func(t *testing.T) {
+		kubeConfigDir, err := os.MkdirTemp(os.TempDir(), "kube")
+		require.NoError(t, err)
+		defer func() {
+			_ = os.RemoveAll(kubeConfigDir)
+		}()
+		kubeConfigPath, err := test.SetupKubeConfig(kubeConfigDir, test.EmptyKubeConfig)
+		require.NoError(t, err)
+
+		require.NoError(t, KubeConfigCheck(kubeConfigPath).Run())
+		require.Error(t, KubeConfigCheck("").Run())
+		require.Error(t, KubeConfigCheck(filepath.Join(kubeConfigDir, "missing")).Run())
+
+		require.NoError(t, os.Chmod(kubeConfigPath, 0400))
+		if os.Geteuid() != 0 {
+			require.Error(t, KubeConfigCheck(kubeConfigPath).Run())
+		}
+	})
+
+	t.Run("test disk check", func(t *testing.T) {
+		stats := system.Stats{Disk: system.Resource{Total: 100 << 30, Free: 2 << 30}}
+		require.NoError(t, DiskCheck(test.NewMockStatsProvider(stats, nil), 1<<30).Run())
+		require.Error(t, DiskCheck(test.NewMockStatsProvider(stats, nil), 4<<30).Run())
+		require.Error(t, DiskCheck(test.NewMockStatsProvider(system.Stats{}, errors.New("failed to read stats")), 0).Run())
+	})
+}
@@ -2,13 +2,37 @@ package kind
 
 import (
 	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cmd/kind/version"
+	"sigs.k8s.io/kind/pkg/exec"
+	"strings"
+	"time"
 )
 
+// ProviderDocker is the name of the Kind Provider running the nodes as Docker containers
+const ProviderDocker = "docker"
+
+// SupportedProviders lists the names of the Kind Providers the client can run the nodes with
+var SupportedProviders = []string{ProviderDocker}
+
+// Node describes a node container of a Kind cluster
+type Node struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+	Image string `json:"image"`
+}
+
 // Client defines methods required to interact with Kind
 type Client interface {
 	CreateCluster(name string, spec []byte) error
 	DeleteCluster(name string) error
 	ClusterHasNodes(name string) (bool, error)
+	ListClusters() ([]string, error)
+	CountNodes(name string) (int, error)
+	ListNodes(name string) ([]Node, error)
+	CreationTime(name string) (time.Time, error)
+	KubeConfig(name string) (string, error)
+	RuntimeVersion() (string, error)
+	Version() string
 }
 
 // ProviderClient implements interaction with Kind Provider
@@ -42,6 +66,93 @@ func (c *ProviderClient) ClusterHasNodes(name string) (bool, error) {
 	return len(clusterNodes) > 0, err
 }
 
+// ListClusters executes the Kind Provider command to list the names of all existing clusters
+func (c *ProviderClient) ListClusters() ([]string, error) {
+	return c.provider.List()
+}
+
+// CountNodes executes the Kind Provider command to count the node containers of the specified cluster
+func (c *ProviderClient) CountNodes(name string) (int, error) {
+	clusterNodes, err := c.provider.ListNodes(name)
+	if err != nil {
+		return 0, err
+	}
+	return len(clusterNodes), nil
+}
+
+// ListNodes executes the Kind Provider command to list the node containers of the specified cluster with their roles and images
+func (c *ProviderClient) ListNodes(name string) ([]Node, error) {
+	clusterNodes, err := c.provider.ListNodes(name)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []Node
+	for _, clusterNode := range clusterNodes {
+		role, err := clusterNode.Role()
+		if err != nil {
+			return nil, err
+		}
+		lines, err := exec.OutputLines(exec.Command(ProviderDocker, "inspect", "--format", "{{.Config.Image}}", clusterNode.String()))
+		if err != nil {
+			return nil, err
+		}
+		image := ""
+		if len(lines) > 0 {
+			image = strings.TrimSpace(lines[0])
+		}
+		nodes = append(nodes, Node{Name: clusterNode.String(), Role: role, Image: image})
+	}
+	return nodes, nil
+}
+
+// CreationTime reads the time the first node container of the specified cluster was created, zero time is returned for clusters without nodes
+func (c *ProviderClient) CreationTime(name string) (time.Time, error) {
+	clusterNodes, err := c.provider.ListNodes(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var created time.Time
+	for _, clusterNode := range clusterNodes {
+		lines, err := exec.OutputLines(exec.Command(ProviderDocker, "inspect", "--format", "{{.Created}}", clusterNode.String()))
+		if err != nil {
+			return time.Time{}, err
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		nodeCreated, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(lines[0]))
+		if err != nil {
+			return time.Time{}, err
+		}
+		if created.IsZero() || nodeCreated.Before(created) {
+			created = nodeCreated
+		}
+	}
+	return created, nil
+}
+
+// KubeConfig executes the Kind Provider command to read the external kubeconfig of the specified cluster
+func (c *ProviderClient) KubeConfig(name string) (string, error) {
+	return c.provider.KubeConfig(name, false)
+}
+
+// RuntimeVersion reads the version of the container runtime running the nodes
+func (c *ProviderClient) RuntimeVersion() (string, error) {
+	lines, err := exec.OutputLines(exec.Command(ProviderDocker, "version", "--format", "{{.Server.Version}}"))
+	if err != nil {
+		return "", err
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.TrimSpace(lines[0]), nil
+}
+
+// Version returns the version of the Kind library
+func (c *ProviderClient) Version() string {
+	return version.Version()
+}
+
 func parseClusterNamesFromCommandOutput(clusterNames []string) map[string]bool {
 	parsedClusterNames := make(map[string]bool)
 	for _, clusterName := range clusterNames {
@@ -2,26 +2,60 @@ package main
 
 import (
 	"fmt"
+	"k8s.io/client-go/util/homedir"
 	"kind-wrapper-api/api"
+	"kind-wrapper-api/health"
 	"kind-wrapper-api/kind"
 	"kind-wrapper-api/kubernetes"
 	"kind-wrapper-api/service"
+	"kind-wrapper-api/system"
 	"os"
+	"path/filepath"
 	"strconv"
+	"time"
 )
 
 const (
-	apiHostEnvKey = "API_HOST"
-	apiPortEnvKey = "API_PORT"
+	apiHostEnvKey          = "API_HOST"
+	apiPortEnvKey          = "API_PORT"
+	apiTokenEnvKey         = "API_TOKEN"
+	apiTLSCertFileEnvKey   = "API_TLS_CERT_FILE"
+	apiTLSKeyFileEnvKey    = "API_TLS_KEY_FILE"
+	hostDiskPathEnvKey     = "HOST_DISK_PATH"
+	readyMinFreeDiskEnvKey = "READY_MIN_FREE_DISK"
+	stateDirEnvKey         = "STATE_DIR"
+	reaperIntervalEnvKey   = "REAPER_INTERVAL"
 
-	defaultApiHost = "0.0.0.0"
-	defaultApiPort = 8888
+	defaultApiHost          = "0.0.0.0"
+	defaultApiPort          = 8888
+	defaultHostDiskPath     = "/"
+	defaultReadyMinFreeDisk = 1 << 30
+	defaultStateDirName     = ".kind-wrapper-api"
+	defaultReaperInterval   = time.Minute
 )
 
 func main() {
 	kubeConfigPath := kubernetes.GetKubeConfigPath()
 	kindClient := kind.NewProviderClient(kubeConfigPath)
-	kindService := service.NewKindService(kindClient, kubeConfigPath)
+	stateDir := os.Getenv(stateDirEnvKey)
+	if stateDir == "" {
+		stateDir = filepath.Join(homedir.HomeDir(), defaultStateDirName)
+	}
+	owners, err := service.NewOwnerStore(stateDir)
+	if err != nil {
+		fmt.Println(fmt.Sprintf("Failed to load cluster owners from %s: %s", stateDir, err))
+		os.Exit(1)
+	}
+	kindService := service.NewKindService(kindClient, kubeConfigPath).WithOwnerStore(owners)
+
+	// The reaper deletes the clusters whose TTL passed, it is disabled by a zero interval
+	reaperInterval, err := time.ParseDuration(os.Getenv(reaperIntervalEnvKey))
+	if err != nil {
+		reaperInterval = defaultReaperInterval
+	}
+	if reaperInterval > 0 {
+		kindService.StartReaper(reaperInterval)
+	}
 
 	host := os.Getenv(apiHostEnvKey)
 	if host == "" {
@@ -34,7 +68,26 @@ func main() {
 		port = defaultApiPort
 	}
 
-	if err := api.NewAPI(host, port, kindService).Start(); err != nil {
+	diskPath := os.Getenv(hostDiskPathEnvKey)
+	if diskPath == "" {
+		diskPath = defaultHostDiskPath
+	}
+	statsProvider := system.NewSystemProvider(diskPath)
+
+	minFreeDisk, err := strconv.ParseInt(os.Getenv(readyMinFreeDiskEnvKey), 10, 64)
+	if err != nil {
+		minFreeDisk = defaultReadyMinFreeDisk
+	}
+
+	server := api.NewAPI(host, port, kindService, statsProvider).
+		WithToken(os.Getenv(apiTokenEnvKey)).
+		WithTLS(os.Getenv(apiTLSCertFileEnvKey), os.Getenv(apiTLSKeyFileEnvKey)).
+		WithReadinessChecks(
+			health.RuntimeCheck(kindClient),
+			health.KubeConfigCheck(kubeConfigPath),
+			health.DiskCheck(statsProvider, minFreeDisk),
+		)
+	if err := server.Start(); err != nil {
 		fmt.Println(fmt.Sprintf("Failed to start API: %s", err))
 	}
 }
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+)
+
+const (
+	kindClusterConfigKind       = "Cluster"
+	kindClusterConfigAPIVersion = "kind.x-k8s.io/v1alpha4"
+)
+
+// InvalidClusterConfigError is returned by the ParseClusterConfig function in case the payload is not a valid Kind config
+var InvalidClusterConfigError = errors.New("invalid kind cluster config")
+
+// ParseClusterConfig decodes a Kind cluster configuration in the kind.x-k8s.io/v1alpha4 format
+// The decoding is strict - unknown and duplicate fields are rejected instead of being silently dropped
+// An error wrapping InvalidClusterConfigError is returned in case the config cannot be used to create a cluster
+func ParseClusterConfig(reader io.Reader) (*v1alpha4.Cluster, error) {
+	var config v1alpha4.Cluster
+	decoder := yaml.NewDecoder(reader)
+	decoder.SetStrict(true)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("%w: %s", InvalidClusterConfigError, err)
+	}
+	if config.Kind != kindClusterConfigKind || config.APIVersion != kindClusterConfigAPIVersion {
+		return nil, fmt.Errorf("%w: unsupported kind %q and apiVersion %q", InvalidClusterConfigError, config.Kind, config.APIVersion)
+	}
+	if config.Name == "" {
+		return nil, fmt.Errorf("%w: cluster name is required", InvalidClusterConfigError)
+	}
+	return &config, nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"strings"
+	"testing"
+)
+
+const fullClusterConfig = `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+name: default-kind
+featureGates:
+  EphemeralContainers: true
+runtimeConfig:
+  api/alpha: "false"
+networking:
+  ipFamily: dual
+  apiServerAddress: 127.0.0.1
+  apiServerPort: 6443
+  podSubnet: 10.244.0.0/16
+  serviceSubnet: 10.96.0.0/12
+  disableDefaultCNI: true
+  kubeProxyMode: ipvs
+  dnsSearch:
+  - example.com
+kubeadmConfigPatches:
+- |
+  kind: ClusterConfiguration
+  networking:
+    dnsDomain: cluster.test
+kubeadmConfigPatchesJSON6902:
+- group: kubeadm.k8s.io
+  version: v1beta3
+  kind: ClusterConfiguration
+  patch: |
+    - op: add
+      path: /apiServer/certSANs/-
+      value: my-hostname
+containerdConfigPatches:
+- |-
+  [plugins."io.containerd.grpc.v1.cri".registry.mirrors."localhost:5001"]
+    endpoint = ["http://kind-registry:5000"]
+containerdConfigPatchesJSON6902:
+- |
+  - op: remove
+    path: /plugins
+nodes:
+- role: control-plane
+  image: kindest/node:v1.26.3
+  labels:
+    tier: frontend
+  extraMounts:
+  - hostPath: /tmp
+    containerPath: /data
+    readOnly: true
+    propagation: HostToContainer
+  extraPortMappings:
+  - containerPort: 80
+    hostPort: 8080
+    protocol: udp
+  kubeadmConfigPatchesJSON6902:
+  - group: kubeadm.k8s.io
+    version: v1beta3
+    kind: InitConfiguration
+    patch: |
+      - op: add
+        path: /nodeRegistration/name
+        value: node
+- role: worker
+`
+
+func TestParseClusterConfig(t *testing.T) {
+	t.Run("test parse full config", func(t *testing.T) {
+		config, err := ParseClusterConfig(strings.NewReader(fullClusterConfig))
+		require.NoError(t, err)
+		require.Equal(t, "default-kind", config.Name)
+		require.Equal(t, v1alpha4.DualStackFamily, config.Networking.IPFamily)
+		require.Equal(t, &[]string{"example.com"}, config.Networking.DNSSearch)
+		require.Len(t, config.KubeadmConfigPatches, 1)
+		require.Len(t, config.KubeadmConfigPatchesJSON6902, 1)
+		require.Len(t, config.ContainerdConfigPatches, 1)
+		require.Len(t, config.ContainerdConfigPatchesJSON6902, 1)
+		require.Len(t, config.Nodes, 2)
+		require.Equal(t, map[string]string{"tier": "frontend"}, config.Nodes[0].Labels)
+		require.Equal(t, v1alpha4.MountPropagationHostToContainer, config.Nodes[0].ExtraMounts[0].Propagation)
+		require.Equal(t, v1alpha4.PortMappingProtocolUDP, config.Nodes[0].ExtraPortMappings[0].Protocol)
+		require.Equal(t, "InitConfiguration", config.Nodes[0].KubeadmConfigPatchesJSON6902[0].Kind)
+		require.Equal(t, v1alpha4.WorkerRole, config.Nodes[1].Role)
+	})
+
+	t.Run("test reject unknown fields", func(t *testing.T) {
+		_, err := ParseClusterConfig(strings.NewReader(fullClusterConfig + "controlPlaneEndpoint:\n  host: 127.0.0.1\n"))
+		require.True(t, errors.Is(err, InvalidClusterConfigError))
+
+		config := strings.Replace(fullClusterConfig, "  labels:", "  nodeLabels:", 1)
+		_, err = ParseClusterConfig(strings.NewReader(config))
+		require.True(t, errors.Is(err, InvalidClusterConfigError))
+	})
+
+	t.Run("test reject invalid values", func(t *testing.T) {
+		config := strings.Replace(fullClusterConfig, "propagation: HostToContainer", "propagation: Sideways", 1)
+		_, err := ParseClusterConfig(strings.NewReader(config))
+		require.True(t, errors.Is(err, InvalidClusterConfigError))
+	})
+
+	t.Run("test reject unsupported kind and version", func(t *testing.T) {
+		config := strings.Replace(fullClusterConfig, "kind.x-k8s.io/v1alpha4", "kind.x-k8s.io/v1alpha3", 1)
+		_, err := ParseClusterConfig(strings.NewReader(config))
+		require.True(t, errors.Is(err, InvalidClusterConfigError))
+
+		_, err = ParseClusterConfig(strings.NewReader(""))
+		require.True(t, errors.Is(err, InvalidClusterConfigError))
+	})
+
+	t.Run("test reject missing name", func(t *testing.T) {
+		config := strings.Replace(fullClusterConfig, "name: default-kind\n", "", 1)
+		_, err := ParseClusterConfig(strings.NewReader(config))
+		require.True(t, errors.Is(err, InvalidClusterConfigError))
+	})
+}
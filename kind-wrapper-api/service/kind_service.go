@@ -1,12 +1,17 @@
 package service
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"gopkg.in/yaml.v2"
 	"kind-wrapper-api/kind"
+	"k8s.io/apimachinery/pkg/labels"
 	"kind-wrapper-api/kubernetes"
 	"log"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -19,51 +24,278 @@ const (
 // KindClusterNotFoundError is returned by the GetClusterState method in case the cluster does not exist
 var KindClusterNotFoundError = errors.New("cluster not found in kind")
 
+// InvalidContinueTokenError is returned by the ListClusters method in case the continue token cannot be decoded
+var InvalidContinueTokenError = errors.New("invalid continue token")
+
 // KindService provides information about Kind clusters based on data read from Kind CLI
+// Errors of failed creations are kept until the cluster is created again or deleted
+// The owners of the created clusters, their labels and expiration times are recorded in the owner store until the clusters are deleted
 type KindService struct {
 	kindClient kind.Client
 	kubeConfigPath string
+	failuresMutex sync.RWMutex
+	failures map[string]error
+	owners *OwnerStore
 }
 
-// NewKindService creates a new instance of KindService
+// NewKindService creates a new instance of KindService, the owners of the clusters are kept only in memory
 func NewKindService(kindClient kind.Client, kubeConfigPath string) *KindService {
-	return &KindService{kindClient: kindClient, kubeConfigPath: kubeConfigPath}
+	owners, _ := NewOwnerStore("")
+	return &KindService{kindClient: kindClient, kubeConfigPath: kubeConfigPath, failures: map[string]error{}, owners: owners}
+}
+
+// WithOwnerStore makes the service record the owners of the clusters in the specified store
+func (s *KindService) WithOwnerStore(owners *OwnerStore) *KindService {
+	s.owners = owners
+	return s
 }
 
 // CreateCluster creates a new Kind cluster from the provided specifications
+// The owner is recorded before the creation starts, so that a cluster is never left without it, an empty owner is not recorded
+// The labels and the expiration time of the owner are recorded with it, the labels are not applied to the nodes of the cluster:
+// Kind v0.18 runs the node containers with its own labels only and offers no option for additional ones
 // The method waits for the cluster to appear in the output of "kind get clusters"
 // or for the creation of the cluster to complete - whichever comes first
 // An error is returned in case the cluster could not be created
-func (s *KindService) CreateCluster(spec ClusterConfig) error {
+func (s *KindService) CreateCluster(spec *v1alpha4.Cluster, owner ClusterOwner) error {
+	if err := s.owners.SetOwner(spec.Name, owner); err != nil {
+		return fmt.Errorf("failed to record owner of cluster %s: %w", spec.Name, err)
+	}
 	chanCreate := make(chan int, 1)
 	chanWait := make(chan int, 1)
 	waitTicker := time.NewTicker(500 * time.Millisecond)
 
+	s.setFailure(spec.Name, nil)
 	go awaitCluster(s.kindClient, spec.Name, waitTicker, chanCreate, chanWait)
-	go executeAndNotifyCreateCluster(s.kindClient, spec, chanCreate)
+	go s.executeAndNotifyCreateCluster(spec, chanCreate)
 
 	result := <-chanWait
 	waitTicker.Stop()
 
 	if result == kindClusterCreationResultFailure {
+		if err := s.getFailure(spec.Name); err != nil {
+			return fmt.Errorf("failed to create cluster %s: %w", spec.Name, err)
+		}
 		return fmt.Errorf("failed to create cluster %s", spec.Name)
 	}
 	return nil
 }
 
 // DeleteCluster calls Kind CLI to delete an existing cluster
-// The deletion is asynchronous, the output is ignored
+// The deletion is asynchronous, the output is ignored and the owner is removed once the cluster is deleted
 // Successful deletion can be verified by calling the GetClusterState method
 func (s *KindService) DeleteCluster(name string) {
+	s.setFailure(name, nil)
 	go func(name string) {
-		_ = s.kindClient.DeleteCluster(name)
+		if err := s.kindClient.DeleteCluster(name); err == nil {
+			if err := s.owners.SetOwner(name, ClusterOwner{}); err != nil {
+				log.Printf("Failed to remove owner of cluster %s: %s\n", name, err)
+			}
+		}
 	}(name)
 }
 
+// ListClusters lists the existing clusters matching the options with their owners and labels, ordered by their names
+// Clusters which have not been created by the service are listed as well, without an owner
+// The state and creation time of a cluster are read and included only if the clusters are filtered by them
+// At most the limit of clusters is listed in case it is set, the returned continue token lists the next ones
+// InvalidContinueTokenError is returned in case the continue token cannot be decoded
+func (s *KindService) ListClusters(options ListClustersOptions) ([]KindClusterListItem, string, error) {
+	after := ""
+	if options.Continue != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(options.Continue)
+		if err != nil || len(decoded) == 0 {
+			return nil, "", InvalidContinueTokenError
+		}
+		after = string(decoded)
+	}
+	clusterNames, err := s.kindClient.ListClusters()
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Strings(clusterNames)
+	clusters := []KindClusterListItem{}
+	for _, clusterName := range clusterNames {
+		if after != "" && clusterName <= after {
+			continue
+		}
+		if options.Limit > 0 && len(clusters) == options.Limit {
+			return clusters, base64.RawURLEncoding.EncodeToString([]byte(clusters[len(clusters)-1].Name)), nil
+		}
+		cluster, matches, err := s.listItem(clusterName, options)
+		if err != nil {
+			return nil, "", err
+		}
+		if matches {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters, "", nil
+}
+
+// listItem checks if a cluster matches the options of the ListClusters method, the cheap checks come first
+func (s *KindService) listItem(clusterName string, options ListClustersOptions) (KindClusterListItem, bool, error) {
+	owner := s.owners.Owner(clusterName)
+	cluster := KindClusterListItem{Name: clusterName, Owner: owner.Owner, Manager: owner.Manager, Labels: owner.Labels, ExpiresAt: owner.ExpiresAt}
+	if options.Selector != nil && !options.Selector.Matches(labels.Set(owner.Labels)) {
+		return cluster, false, nil
+	}
+	if options.OlderThan > 0 {
+		created, err := s.kindClient.CreationTime(clusterName)
+		if err != nil {
+			return cluster, false, err
+		}
+		if created.IsZero() || time.Since(created) < options.OlderThan {
+			return cluster, false, nil
+		}
+		cluster.CreatedAt = &created
+	}
+	if options.State != "" {
+		state, err := s.GetClusterState(clusterName)
+		if errors.Is(err, KindClusterNotFoundError) {
+			return cluster, false, nil
+		} else if err != nil {
+			return cluster, false, err
+		}
+		if state.State != options.State {
+			return cluster, false, nil
+		}
+		cluster.State = state.State
+	}
+	return cluster, true, nil
+}
+
+// SetClusterOwner records the owner of an existing cluster, an empty owner releases the cluster
+// Nil labels keep the recorded labels, the labels of the nodes are not changed in any case
+// The expiration time of an owned cluster is kept, it is changed by the SetClusterTTL method only
+// The expiration time of a released cluster is cleared, so that the reaper does not delete the cluster kept by its former owner
+// KindClusterNotFoundError is returned in case the cluster does not exist
+func (s *KindService) SetClusterOwner(name string, owner ClusterOwner) error {
+	exists, err := s.clusterExists(name)
+	if err != nil {
+		return err
+	} else if !exists {
+		return KindClusterNotFoundError
+	}
+	recorded := s.owners.Owner(name)
+	if owner.Labels == nil {
+		owner.Labels = recorded.Labels
+	}
+	owner.ExpiresAt = nil
+	if owner.Owner != "" {
+		owner.ExpiresAt = recorded.ExpiresAt
+	}
+	return s.owners.SetOwner(name, owner)
+}
+
+// SetClusterTTL sets the time an existing cluster is kept from now on until the reaper deletes it, a zero TTL keeps the cluster until deleted
+// KindClusterNotFoundError is returned in case the cluster does not exist
+func (s *KindService) SetClusterTTL(name string, ttl time.Duration) error {
+	exists, err := s.clusterExists(name)
+	if err != nil {
+		return err
+	} else if !exists {
+		return KindClusterNotFoundError
+	}
+	owner := s.owners.Owner(name)
+	owner.ExpiresAt = nil
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		owner.ExpiresAt = &expiresAt
+	}
+	return s.owners.SetOwner(name, owner)
+}
+
+// StartReaper deletes the expired clusters every interval in the background, so that the clusters are deleted even if their owners are gone
+func (s *KindService) StartReaper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.DeleteExpiredClusters()
+		}
+	}()
+}
+
+// DeleteExpiredClusters deletes the clusters whose expiration time passed
+// The records of expired clusters which do not exist anymore, e.g. deleted by Kind CLI directly, are removed
+func (s *KindService) DeleteExpiredClusters() {
+	expired := s.owners.Expired(time.Now())
+	if len(expired) == 0 {
+		return
+	}
+	clusterNames, err := s.kindClient.ListClusters()
+	if err != nil {
+		log.Printf("Failed to list clusters to delete the expired ones: %s\n", err)
+		return
+	}
+	existing := map[string]bool{}
+	for _, clusterName := range clusterNames {
+		existing[clusterName] = true
+	}
+	for _, name := range expired {
+		if !existing[name] {
+			if err := s.owners.SetOwner(name, ClusterOwner{}); err != nil {
+				log.Printf("Failed to remove owner of cluster %s: %s\n", name, err)
+			}
+			continue
+		}
+		log.Printf("Deleting expired cluster %s\n", name)
+		s.DeleteCluster(name)
+	}
+}
+
+// clusterExists checks if a cluster with the specified name is listed by Kind
+func (s *KindService) clusterExists(name string) (bool, error) {
+	clusterNames, err := s.kindClient.ListClusters()
+	if err != nil {
+		return false, err
+	}
+	for _, clusterName := range clusterNames {
+		if clusterName == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// KindVersion returns the version of the Kind library used to create clusters
+func (s *KindService) KindVersion() string {
+	return s.kindClient.Version()
+}
+
+// GetKindInfo reads the versions of Kind and its container runtime and counts all existing clusters and their nodes
+// Clusters which have not been created by the service are counted as well
+func (s *KindService) GetKindInfo() (KindInfo, error) {
+	runtimeVersion, err := s.kindClient.RuntimeVersion()
+	if err != nil {
+		return KindInfo{}, err
+	}
+	clusterNames, err := s.kindClient.ListClusters()
+	if err != nil {
+		return KindInfo{}, err
+	}
+	info := KindInfo{
+		Provider: kind.ProviderDocker,
+		RuntimeVersion: runtimeVersion,
+		KindVersion: s.kindClient.Version(),
+		Clusters: len(clusterNames),
+	}
+	for _, clusterName := range clusterNames {
+		nodes, err := s.kindClient.CountNodes(clusterName)
+		if err != nil {
+			return KindInfo{}, err
+		}
+		info.Nodes += nodes
+	}
+	return info, nil
+}
+
 // GetClusterState checks if a cluster with a specified name exists and returns its state:
 // Running state is returned in case the cluster exists and is ready to be used
 // Pending state is returned in case the cluster exists but is not ready
 // Failed state is returned in case the cluster exists but Kind does not know about it
+// or in case the last creation of the cluster failed, the reason and message describe the failure
 // KindClusterNotFoundError is returned in case the cluster does not exist
 // A generic error is returned in case the cluster info could not be retrieved
 func (s *KindService) GetClusterState(clusterName string) (KindClusterStatus, error) {
@@ -78,24 +310,52 @@ func (s *KindService) GetClusterState(clusterName string) (KindClusterStatus, er
 
 	clusterConfig, clusterHasConfig := clusterConfigs[kindClusterContextName(clusterName)]
 
-	if !clusterHasNodes && !clusterHasConfig {
+	if failure := s.getFailure(clusterName); failure != nil {
+		return NewFailedKindClusterStatus(KindClusterReasonCreateFailed, failure.Error()), nil
+	} else if !clusterHasNodes && !clusterHasConfig {
 		return NewKindClusterStatus(KindClusterStateUnknown, ""), KindClusterNotFoundError
 	} else if !clusterHasNodes && clusterHasConfig {
-		return NewKindClusterStatus(KindClusterStateFailed, ""), nil
+		return NewFailedKindClusterStatus(KindClusterReasonNodesMissing, "cluster is present in kubeconfig but has no nodes"), nil
 	} else if clusterHasNodes && !clusterHasConfig {
 		return NewKindClusterStatus(KindClusterStatePending, ""), nil
 	}
 	return NewKindClusterStatus(KindClusterStateRunning, clusterConfig.Server), nil
 }
 
-func executeAndNotifyCreateCluster(client kind.Client, spec ClusterConfig, chanCreate chan <- int) {
+// GetClusterDetails looks up a cluster by its Kind name and returns its state, nodes and kubeconfig
+// Clusters which have not been created by the service can be looked up as well
+// KindClusterNotFoundError is returned in case the cluster does not exist
+func (s *KindService) GetClusterDetails(clusterName string) (KindClusterDetails, error) {
+	state, err := s.GetClusterState(clusterName)
+	if err != nil {
+		return KindClusterDetails{}, err
+	}
+	nodes, err := s.kindClient.ListNodes(clusterName)
+	if err != nil {
+		return KindClusterDetails{}, err
+	}
+	details := KindClusterDetails{Name: clusterName, KindClusterStatus: state, Nodes: nodes}
+	if details.Nodes == nil {
+		details.Nodes = []kind.Node{}
+	}
+	if state.State == KindClusterStateRunning {
+		if details.KubeConfig, err = s.kindClient.KubeConfig(clusterName); err != nil {
+			return KindClusterDetails{}, err
+		}
+	}
+	return details, nil
+}
+
+func (s *KindService) executeAndNotifyCreateCluster(spec *v1alpha4.Cluster, chanCreate chan <- int) {
 	specBytes, err := yaml.Marshal(spec)
 	log.Printf("Creating cluster from %s\n", specBytes)
 	if err != nil {
 		log.Printf("Creation of cluster %s failed\n", spec.Name)
+		s.setFailure(spec.Name, err)
 		chanCreate <- kindClusterCreationResultFailure
-	} else if err = client.CreateCluster(spec.Name, specBytes); err != nil {
-		log.Printf("Creation of cluster %s failed\n", spec.Name)
+	} else if err = s.kindClient.CreateCluster(spec.Name, specBytes); err != nil {
+		log.Printf("Creation of cluster %s failed: %s\n", spec.Name, err)
+		s.setFailure(spec.Name, err)
 		chanCreate <- kindClusterCreationResultFailure
 	} else {
 		log.Printf("Creation of cluster %s succeeded\n", spec.Name)
@@ -129,6 +389,23 @@ func awaitCluster(client kind.Client, name string, ticker *time.Ticker, chanCrea
 	}
 }
 
+// setFailure records an error of the last creation of a cluster, nil clears the error
+func (s *KindService) setFailure(name string, err error) {
+	s.failuresMutex.Lock()
+	defer s.failuresMutex.Unlock()
+	if err == nil {
+		delete(s.failures, name)
+	} else {
+		s.failures[name] = err
+	}
+}
+
+func (s *KindService) getFailure(name string) error {
+	s.failuresMutex.RLock()
+	defer s.failuresMutex.RUnlock()
+	return s.failures[name]
+}
+
 func kindClusterContextName(clusterName string) string {
 	return kindClusterContextPrefix + clusterName
 }
@@ -3,8 +3,11 @@ package service
 import (
 	"errors"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
+	"kind-wrapper-api/kind"
 	"kind-wrapper-api/test"
 	"os"
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
 	"testing"
 	"time"
 )
@@ -72,9 +75,9 @@ func TestKindService(t *testing.T) {
 			return nil
 		})
 
-		spec := ClusterConfig{Name: "kind"}
+		spec := &v1alpha4.Cluster{Name: "kind"}
 		kindService := NewKindService(mockKindClient, kubeConfigPath)
-		err = kindService.CreateCluster(spec)
+		err = kindService.CreateCluster(spec, ClusterOwner{})
 		require.NoError(t, err)
 	})
 
@@ -97,9 +100,9 @@ func TestKindService(t *testing.T) {
 			return errors.New("failed to create cluster")
 		})
 
-		spec := ClusterConfig{Name: "kind"}
+		spec := &v1alpha4.Cluster{Name: "kind"}
 		kindService := NewKindService(mockKindClient, kubeConfigPath)
-		err = kindService.CreateCluster(spec)
+		err = kindService.CreateCluster(spec, ClusterOwner{})
 		require.Error(t, err)
 
 		mockKindClient.ClearHasNodesQueue()
@@ -113,7 +116,227 @@ func TestKindService(t *testing.T) {
 			time.Sleep(3 * time.Second)
 			return nil
 		})
-		err = kindService.CreateCluster(spec)
+		err = kindService.CreateCluster(spec, ClusterOwner{})
 		require.Error(t, err)
 	})
+
+	t.Run("test get kind info", func(t *testing.T) {
+		mockKindClient := test.NewMockKindClient()
+		mockKindClient.SetClusters(map[string]int{"kind": 1, "kind-2": 2})
+		kindService := NewKindService(mockKindClient, kubeConfigPath)
+
+		info, err := kindService.GetKindInfo()
+		require.NoError(t, err)
+		require.Equal(t, KindInfo{Provider: "docker", RuntimeVersion: "20.10.17", KindVersion: test.MockKindVersion, Clusters: 2, Nodes: 3}, info)
+
+		mockKindClient.SetRuntimeVersion("", errors.New("docker not running"))
+		_, err = kindService.GetKindInfo()
+		require.Error(t, err)
+	})
+
+	t.Run("test get cluster state after creation failure", func(t *testing.T) {
+		mockKindClient := test.NewMockKindClient()
+		mockKindClient.SetCreate(func() error {
+			return errors.New("node image not found")
+		})
+
+		spec := &v1alpha4.Cluster{Name: "kind-failed"}
+		kindService := NewKindService(mockKindClient, kubeConfigPath)
+		err = kindService.CreateCluster(spec, ClusterOwner{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "node image not found")
+
+		state, err := kindService.GetClusterState("kind-failed")
+		require.NoError(t, err)
+		require.Equal(t, KindClusterStateFailed, state.State)
+		require.Equal(t, KindClusterReasonCreateFailed, state.Reason)
+		require.Equal(t, "node image not found", state.Message)
+
+		kindService.DeleteCluster("kind-failed")
+		_, err = kindService.GetClusterState("kind-failed")
+		require.ErrorIs(t, err, KindClusterNotFoundError)
+
+		state, err = kindService.GetClusterState("kind")
+		require.NoError(t, err)
+		require.Equal(t, KindClusterStateFailed, state.State)
+		require.Equal(t, KindClusterReasonNodesMissing, state.Reason)
+	})
+
+	t.Run("test get cluster details", func(t *testing.T) {
+		mockKindClient := test.NewMockKindClient()
+		mockKindClient.SetDefaultHasNodes(func() (bool, error) {
+			return true, nil
+		})
+		nodes := []kind.Node{
+			{Name: "kind-control-plane", Role: "control-plane", Image: "kindest/node:v1.26.3"},
+			{Name: "kind-worker", Role: "worker", Image: "kindest/node:v1.26.3"},
+		}
+		mockKindClient.SetNodes("kind", nodes)
+		kindService := NewKindService(mockKindClient, kubeConfigPath)
+
+		details, err := kindService.GetClusterDetails("kind")
+		require.NoError(t, err)
+		require.Equal(t, "kind", details.Name)
+		require.Equal(t, KindClusterStateRunning, details.State)
+		require.Equal(t, "127.0.0.1", details.Host)
+		require.Equal(t, 8080, details.Port)
+		require.Equal(t, nodes, details.Nodes)
+		require.Equal(t, test.KubeConfig, details.KubeConfig)
+
+		details, err = kindService.GetClusterDetails("kind-2")
+		require.NoError(t, err)
+		require.Equal(t, KindClusterStatePending, details.State)
+		require.Empty(t, details.Nodes)
+		require.Empty(t, details.KubeConfig)
+
+		mockKindClient.SetDefaultHasNodes(func() (bool, error) {
+			return false, nil
+		})
+		_, err = kindService.GetClusterDetails("kind-3")
+		require.ErrorIs(t, err, KindClusterNotFoundError)
+	})
+
+	t.Run("test list clusters with owners", func(t *testing.T) {
+		mockKindClient := test.NewMockKindClient()
+		mockKindClient.SetDefaultHasNodes(func() (bool, error) {
+			return true, nil
+		})
+		mockKindClient.SetClusters(map[string]int{"kind-created": 1, "kind-external": 1})
+		kindService := NewKindService(mockKindClient, kubeConfigPath)
+		labels := map[string]string{"team": "payments"}
+		spec := &v1alpha4.Cluster{Name: "kind-created", Nodes: []v1alpha4.Node{{Role: v1alpha4.WorkerRole}}}
+		require.NoError(t, kindService.CreateCluster(spec, ClusterOwner{Owner: "uid-1", Labels: labels}))
+		require.Empty(t, spec.Nodes[0].Labels)
+
+		clusters, _, err := kindService.ListClusters(ListClustersOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []KindClusterListItem{{Name: "kind-created", Owner: "uid-1", Labels: labels}, {Name: "kind-external"}}, clusters)
+
+		require.NoError(t, kindService.SetClusterOwner("kind-created", ClusterOwner{}))
+		require.NoError(t, kindService.SetClusterOwner("kind-external", ClusterOwner{Owner: "uid-2"}))
+		require.ErrorIs(t, kindService.SetClusterOwner("kind-missing", ClusterOwner{Owner: "uid-3"}), KindClusterNotFoundError)
+		clusters, _, err = kindService.ListClusters(ListClustersOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []KindClusterListItem{{Name: "kind-created", Labels: labels}, {Name: "kind-external", Owner: "uid-2"}}, clusters)
+	})
+
+	t.Run("test list clusters with filters", func(t *testing.T) {
+		mockKindClient := test.NewMockKindClient()
+		mockKindClient.SetDefaultHasNodes(func() (bool, error) {
+			return true, nil
+		})
+		mockKindClient.SetClusters(map[string]int{"kind": 1, "kind-pending": 1, "kind-external": 1})
+		created := time.Now().Add(-48 * time.Hour)
+		mockKindClient.SetCreationTime("kind", created)
+		mockKindClient.SetCreationTime("kind-pending", time.Now().Add(-time.Hour))
+		kindService := NewKindService(mockKindClient, kubeConfigPath)
+		payments := map[string]string{"team": "payments"}
+		require.NoError(t, kindService.SetClusterOwner("kind", ClusterOwner{Owner: "uid-1", Labels: payments}))
+		require.NoError(t, kindService.SetClusterOwner("kind-pending", ClusterOwner{Owner: "uid-2", Labels: map[string]string{"team": "search"}}))
+
+		names := func(clusters []KindClusterListItem) []string {
+			var names []string
+			for _, cluster := range clusters {
+				names = append(names, cluster.Name)
+			}
+			return names
+		}
+
+		selector, err := labels.Parse("team in (payments,search)")
+		require.NoError(t, err)
+		clusters, _, err := kindService.ListClusters(ListClustersOptions{Selector: selector})
+		require.NoError(t, err)
+		require.Equal(t, []string{"kind", "kind-pending"}, names(clusters))
+
+		clusters, _, err = kindService.ListClusters(ListClustersOptions{State: KindClusterStatePending})
+		require.NoError(t, err)
+		require.Equal(t, []KindClusterListItem{
+			{Name: "kind-external", State: KindClusterStatePending},
+			{Name: "kind-pending", Owner: "uid-2", Labels: map[string]string{"team": "search"}, State: KindClusterStatePending},
+		}, clusters)
+
+		clusters, _, err = kindService.ListClusters(ListClustersOptions{OlderThan: 24 * time.Hour, State: KindClusterStateRunning})
+		require.NoError(t, err)
+		require.Equal(t, []KindClusterListItem{{Name: "kind", Owner: "uid-1", Labels: payments, State: KindClusterStateRunning, CreatedAt: &created}}, clusters)
+	})
+
+	t.Run("test list clusters in pages", func(t *testing.T) {
+		mockKindClient := test.NewMockKindClient()
+		mockKindClient.SetClusters(map[string]int{"kind-a": 1, "kind-b": 1, "kind-c": 1})
+		kindService := NewKindService(mockKindClient, kubeConfigPath)
+
+		var listed []KindClusterListItem
+		continueToken := ""
+		for pages := 1; ; pages++ {
+			clusters, next, err := kindService.ListClusters(ListClustersOptions{Limit: 2, Continue: continueToken})
+			require.NoError(t, err)
+			listed = append(listed, clusters...)
+			if next == "" {
+				require.Equal(t, 2, pages)
+				break
+			}
+			continueToken = next
+		}
+		require.Equal(t, []KindClusterListItem{{Name: "kind-a"}, {Name: "kind-b"}, {Name: "kind-c"}}, listed)
+
+		_, _, err := kindService.ListClusters(ListClustersOptions{Continue: "not base64!"})
+		require.ErrorIs(t, err, InvalidContinueTokenError)
+	})
+
+	t.Run("test delete expired clusters", func(t *testing.T) {
+		mockKindClient := test.NewMockKindClient()
+		mockKindClient.SetDefaultHasNodes(func() (bool, error) {
+			return true, nil
+		})
+		mockKindClient.SetClusters(map[string]int{"kind-expired": 1, "kind-alive": 1})
+		kindService := NewKindService(mockKindClient, kubeConfigPath)
+		expiresAt := time.Now().Add(-time.Minute)
+		require.NoError(t, kindService.CreateCluster(&v1alpha4.Cluster{Name: "kind-expired"}, ClusterOwner{Owner: "uid-1", ExpiresAt: &expiresAt}))
+		require.NoError(t, kindService.SetClusterOwner("kind-alive", ClusterOwner{Owner: "uid-2"}))
+		require.NoError(t, kindService.SetClusterTTL("kind-alive", time.Hour))
+		require.ErrorIs(t, kindService.SetClusterTTL("kind-missing", time.Hour), KindClusterNotFoundError)
+
+		// Recording the owner keeps the expiration time
+		require.NoError(t, kindService.SetClusterOwner("kind-expired", ClusterOwner{Owner: "uid-3"}))
+		require.Equal(t, &expiresAt, kindService.owners.Owner("kind-expired").ExpiresAt)
+
+		kindService.DeleteExpiredClusters()
+		require.Eventually(t, func() bool {
+			return kindService.owners.Owner("kind-expired").IsEmpty()
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, "uid-2", kindService.owners.Owner("kind-alive").Owner)
+		require.NotNil(t, kindService.owners.Owner("kind-alive").ExpiresAt)
+
+		require.NoError(t, kindService.SetClusterTTL("kind-alive", 0))
+		require.Equal(t, ClusterOwner{Owner: "uid-2"}, kindService.owners.Owner("kind-alive"))
+	})
+
+	t.Run("test release clears the expiration time", func(t *testing.T) {
+		mockKindClient := test.NewMockKindClient()
+		mockKindClient.SetDefaultHasNodes(func() (bool, error) {
+			return true, nil
+		})
+		mockKindClient.SetClusters(map[string]int{"kind-kept": 1})
+		kindService := NewKindService(mockKindClient, kubeConfigPath)
+		labels := map[string]string{"team": "payments"}
+		expiresAt := time.Now().Add(-time.Minute)
+		require.NoError(t, kindService.CreateCluster(&v1alpha4.Cluster{Name: "kind-kept"}, ClusterOwner{Owner: "uid-1", Labels: labels, ExpiresAt: &expiresAt}))
+
+		require.NoError(t, kindService.SetClusterOwner("kind-kept", ClusterOwner{}))
+		require.Equal(t, ClusterOwner{Labels: labels}, kindService.owners.Owner("kind-kept"))
+		require.Empty(t, kindService.owners.Expired(time.Now()))
+	})
+
+	t.Run("test remove records of expired clusters deleted outside the service", func(t *testing.T) {
+		mockKindClient := test.NewMockKindClient()
+		mockKindClient.SetDelete(func() error {
+			return errors.New("unexpected deletion")
+		})
+		kindService := NewKindService(mockKindClient, kubeConfigPath)
+		expiresAt := time.Now().Add(-time.Minute)
+		require.NoError(t, kindService.owners.SetOwner("kind-gone", ClusterOwner{Owner: "uid-1", ExpiresAt: &expiresAt}))
+
+		kindService.DeleteExpiredClusters()
+		require.Empty(t, kindService.owners.Owner("kind-gone"))
+	})
 }
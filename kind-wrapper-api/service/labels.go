@@ -0,0 +1,35 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strings"
+)
+
+// InvalidClusterLabelError is returned by the ParseClusterLabels function in case a label is not a valid Kubernetes label
+var InvalidClusterLabelError = errors.New("invalid cluster label")
+
+// ParseClusterLabels decodes labels of a cluster in the key=value format
+// The keys and values have to be valid Kubernetes labels, since the clusters are listed by Kubernetes label selectors
+// An error wrapping InvalidClusterLabelError is returned in case a label is invalid
+func ParseClusterLabels(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	labels := map[string]string{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %q is not in the key=value format", InvalidClusterLabelError, value)
+		}
+		if errs := validation.IsQualifiedName(parts[0]); len(errs) > 0 {
+			return nil, fmt.Errorf("%w: key %q: %s", InvalidClusterLabelError, parts[0], strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(parts[1]); len(errs) > 0 {
+			return nil, fmt.Errorf("%w: value %q: %s", InvalidClusterLabelError, parts[1], strings.Join(errs, ", "))
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return labels, nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestClusterLabels(t *testing.T) {
+	t.Run("test parse labels", func(t *testing.T) {
+		labels, err := ParseClusterLabels([]string{"team=payments", "infrastructure.cluster.x-k8s.io/kindcluster-namespace=default", "empty="})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"team": "payments",
+			"infrastructure.cluster.x-k8s.io/kindcluster-namespace": "default",
+			"empty": "",
+		}, labels)
+
+		labels, err = ParseClusterLabels(nil)
+		require.NoError(t, err)
+		require.Nil(t, labels)
+	})
+
+	t.Run("test parse invalid labels", func(t *testing.T) {
+		for _, value := range []string{"team", "=payments", "team=payments/checkout", "bad key=value"} {
+			_, err := ParseClusterLabels([]string{value})
+			require.ErrorIs(t, err, InvalidClusterLabelError, value)
+		}
+	})
+
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const ownerFileSuffix = ".owner.json"
+
+// ClusterOwner is the record of the owner of a cluster and its metadata, e.g. the namespace and the team of the owner
+// Manager identifies the management cluster of the owner, so that management clusters sharing the service tell their clusters apart
+// ExpiresAt is the time the cluster gets deleted by the reaper of the service, the cluster is kept until deleted if not set
+type ClusterOwner struct {
+	Owner     string            `json:"owner,omitempty"`
+	Manager   string            `json:"manager,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	ExpiresAt *time.Time        `json:"expiresAt,omitempty"`
+}
+
+// IsEmpty checks if the record has neither an owner nor a manager nor labels nor an expiration time
+func (o ClusterOwner) IsEmpty() bool {
+	return o.Owner == "" && o.Manager == "" && len(o.Labels) == 0 && o.ExpiresAt == nil
+}
+
+// OwnerStore records the owners of the clusters created by the service with their labels, so that clusters left behind by their owners can be found
+// Every owner is stored in a file of the cluster in the state directory, so that the owners survive restarts of the API
+// The owners are kept only in memory in case no state directory is configured
+type OwnerStore struct {
+	dir    string
+	mutex  sync.RWMutex
+	owners map[string]ClusterOwner
+}
+
+// NewOwnerStore creates a new instance of OwnerStore and loads the owners stored in the state directory
+// The state directory is created in case it does not exist
+func NewOwnerStore(dir string) (*OwnerStore, error) {
+	store := &OwnerStore{dir: dir, owners: map[string]ClusterOwner{}}
+	if dir == "" {
+		return store, nil
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ownerFileSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var owner ClusterOwner
+		if err := json.Unmarshal(data, &owner); err != nil {
+			return nil, err
+		}
+		store.owners[strings.TrimSuffix(entry.Name(), ownerFileSuffix)] = owner
+	}
+	return store, nil
+}
+
+// Owner returns the owner of a cluster with its labels, an empty record is returned for clusters without an owner
+func (s *OwnerStore) Owner(name string) ClusterOwner {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.owners[name]
+}
+
+// Expired returns the names of the clusters which expired before the specified time, ordered by their names
+func (s *OwnerStore) Expired(now time.Time) []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var names []string
+	for name, owner := range s.owners {
+		if owner.ExpiresAt != nil && owner.ExpiresAt.Before(now) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetOwner records the owner of a cluster with its labels, an empty record removes the record
+func (s *OwnerStore) SetOwner(name string, owner ClusterOwner) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if owner.IsEmpty() {
+		delete(s.owners, name)
+		if s.dir == "" {
+			return nil
+		}
+		if err := os.Remove(s.ownerFile(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	s.owners[name] = owner
+	if s.dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(owner)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.ownerFile(name), data, 0600)
+}
+
+func (s *OwnerStore) ownerFile(name string) string {
+	return filepath.Join(s.dir, name+ownerFileSuffix)
+}
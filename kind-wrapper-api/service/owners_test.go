@@ -0,0 +1,86 @@
+package service
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOwnerStore(t *testing.T) {
+	stateDir, err := os.MkdirTemp(os.TempDir(), "state")
+	require.NoError(t, err)
+
+	defer func() {
+		_ = os.RemoveAll(stateDir)
+	}()
+
+	t.Run("test owners survive restarts", func(t *testing.T) {
+		store, err := NewOwnerStore(stateDir)
+		require.NoError(t, err)
+		require.NoError(t, store.SetOwner("kind", ClusterOwner{Owner: "uid-1"}))
+		require.NoError(t, store.SetOwner("kind-2", ClusterOwner{Owner: "uid-2"}))
+		require.Equal(t, "uid-1", store.Owner("kind").Owner)
+
+		restarted, err := NewOwnerStore(stateDir)
+		require.NoError(t, err)
+		require.Equal(t, "uid-1", restarted.Owner("kind").Owner)
+		require.Equal(t, "uid-2", restarted.Owner("kind-2").Owner)
+		require.Empty(t, restarted.Owner("kind-3"))
+	})
+
+	t.Run("test managers and labels survive restarts", func(t *testing.T) {
+		store, err := NewOwnerStore(stateDir)
+		require.NoError(t, err)
+		owner := ClusterOwner{Owner: "uid-4", Manager: "mgmt-a", Labels: map[string]string{"team": "payments"}}
+		require.NoError(t, store.SetOwner("kind-4", owner))
+
+		restarted, err := NewOwnerStore(stateDir)
+		require.NoError(t, err)
+		require.Equal(t, owner, restarted.Owner("kind-4"))
+
+		// Released clusters keep their labels
+		require.NoError(t, restarted.SetOwner("kind-4", ClusterOwner{Labels: owner.Labels}))
+		restarted, err = NewOwnerStore(stateDir)
+		require.NoError(t, err)
+		require.Equal(t, ClusterOwner{Labels: owner.Labels}, restarted.Owner("kind-4"))
+		require.NoError(t, restarted.SetOwner("kind-4", ClusterOwner{}))
+	})
+
+	t.Run("test expiration times survive restarts", func(t *testing.T) {
+		store, err := NewOwnerStore(stateDir)
+		require.NoError(t, err)
+		expired := time.Now().Add(-time.Minute).UTC()
+		alive := time.Now().Add(time.Hour).UTC()
+		require.NoError(t, store.SetOwner("kind-5", ClusterOwner{ExpiresAt: &expired}))
+		require.NoError(t, store.SetOwner("kind-6", ClusterOwner{Owner: "uid-6", ExpiresAt: &alive}))
+
+		restarted, err := NewOwnerStore(stateDir)
+		require.NoError(t, err)
+		require.True(t, expired.Equal(*restarted.Owner("kind-5").ExpiresAt))
+		require.Equal(t, []string{"kind-5"}, restarted.Expired(time.Now()))
+		require.Equal(t, []string{"kind-5", "kind-6"}, restarted.Expired(alive.Add(time.Second)))
+		require.NoError(t, restarted.SetOwner("kind-5", ClusterOwner{}))
+		require.NoError(t, restarted.SetOwner("kind-6", ClusterOwner{}))
+	})
+
+	t.Run("test empty owner removes record", func(t *testing.T) {
+		store, err := NewOwnerStore(stateDir)
+		require.NoError(t, err)
+		require.NoError(t, store.SetOwner("kind", ClusterOwner{}))
+		require.NoError(t, store.SetOwner("kind-3", ClusterOwner{}))
+		require.Empty(t, store.Owner("kind"))
+
+		restarted, err := NewOwnerStore(stateDir)
+		require.NoError(t, err)
+		require.Empty(t, restarted.Owner("kind"))
+		require.Equal(t, "uid-2", restarted.Owner("kind-2").Owner)
+	})
+
+	t.Run("test owners kept in memory without state directory", func(t *testing.T) {
+		store, err := NewOwnerStore("")
+		require.NoError(t, err)
+		require.NoError(t, store.SetOwner("kind", ClusterOwner{Owner: "uid-1"}))
+		require.Equal(t, "uid-1", store.Owner("kind").Owner)
+	})
+}
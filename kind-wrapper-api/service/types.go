@@ -1,8 +1,11 @@
 package service
 
 import (
+	"k8s.io/apimachinery/pkg/labels"
+	"kind-wrapper-api/kind"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 type KindClusterState string
@@ -12,64 +15,60 @@ const (
 	KindClusterStateRunning = KindClusterState("running")
 	KindClusterStateUnknown = KindClusterState("unknown")
 	KindClusterStateFailed = KindClusterState("failed")
-)
 
-// ExtraPortMappingConfig defines configuration options fpr extra port mappings in NodeConfig
-type ExtraPortMappingConfig struct {
-	ContainerPort int    `yaml:"containerPort,omitempty"`
-	HostPort      int    `yaml:"hostPort,omitempty"`
-	ListenAddress string `yaml:"listenAddress,omitempty"` // optional: set the bind address on the host; default 0.0.0.0
-	Protocol      string `yaml:"protocol,omitempty"` // optional: set the protocol to one of TCP, UDP, SCTP; default TCP
-}
+	KindClusterReasonCreateFailed = "CreateFailed"
+	KindClusterReasonNodesMissing = "NodesMissing"
+)
 
-// ExtraMountConfig defines configuration options for extra mounts in NodeConfig
-type ExtraMountConfig struct {
-	HostPath       string `yaml:"hostPath,omitempty"`
-	ContainerPath  string `yaml:"containerPath,omitempty"`
-	ReadOnly       bool   `yaml:"readOnly,omitempty"`       // optional: if set, the mount is read-only; default false
-	SELinuxRelabel bool   `yaml:"selinuxRelabel,omitempty"`
-	Propagation    string `yaml:"propagation,omitempty"`    // optional: set propagation mode (None, HostToContainer or Bidirectional); default None
-	// see https://kubernetes.io/docs/concepts/storage/volumes/#mount-propagation
+// KindClusterStatus contains information about Kind cluster state and clontrol plane endpoint if available
+// Reason and Message explain the state of failed clusters
+type KindClusterStatus struct {
+	State KindClusterState `json:"state"`
+	Host  string           `json:"host,omitempty"`
+	Port int               `json:"port,omitempty"`
+	Reason  string         `json:"reason,omitempty"`
+	Message string         `json:"message,omitempty"`
 }
 
-// NodeConfig defines configuration options for nodes in ClusterConfig
-type NodeConfig struct {
-	Role                 string                   `yaml:"role"`
-	Image                string                   `yaml:"image,omitempty"`
-	KubeAdmConfigPatches []string                 `yaml:"kubeadmConfigPatches,omitempty"`
-	ExtraMounts          []ExtraMountConfig       `yaml:"extraMounts,omitempty"`
-	ExtraPortMappings    []ExtraPortMappingConfig `yaml:"extraPortMappings,omitempty"`
+// KindClusterDetails contains the state, nodes and kubeconfig of a Kind cluster looked up by its name
+// The kubeconfig is included only for running clusters
+type KindClusterDetails struct {
+	Name string `json:"name"`
+	KindClusterStatus
+	Nodes []kind.Node `json:"nodes"`
+	KubeConfig string `json:"kubeconfig,omitempty"`
 }
 
-// NetworkingConfig defines configuration options for networking in ClusterConfig
-type NetworkingConfig struct {
-	IPFamily          string `yaml:"ipFamily,omitempty"`
-	APIServerAddress  string `yaml:"apiServerAddress,omitempty"`  // By default the API server listens on a random open port.
-	// You may choose a specific port but probably don't need to in most cases.
-	// Using a random port makes it easier to spin up multiple clusters.
-	APIServerPort     int    `yaml:"apiServerPort,omitempty"`
-	PodSubnet         string `yaml:"podSubnet,omitempty"`
-	ServiceSubnet     string `yaml:"serviceSubnet,omitempty"`
-	DisableDefaultCNI bool   `yaml:"disableDefaultCNI,omitempty"` // the default CNI will not be installed
-	KubeProxyMode     string `yaml:"kubeProxyMode,omitempty"`     // iptables (default), ipvs, none
+// KindClusterListItem contains the name of a Kind cluster and the owner, its manager and labels recorded when the service created it
+// The owner is empty for clusters which have not been created by the service or have been released by their owners
+// The state and the creation time are included only if the clusters are filtered by them, the expiration time only if the cluster has a TTL
+type KindClusterListItem struct {
+	Name string `json:"name"`
+	Owner string `json:"owner,omitempty"`
+	Manager string `json:"manager,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	State KindClusterState `json:"state,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
-// ClusterConfig defines configuration options for Kind Cluster
-type ClusterConfig struct {
-	Kind          string            `yaml:"kind"`
-	APIVersion    string            `yaml:"apiVersion"`
-	Name          string            `yaml:"name"`
-	FeatureGates  map[string]bool   `yaml:"featureGates,omitempty"`
-	RuntimeConfig map[string]string `yaml:"runtimeConfig,omitempty"`
-	Networking    NetworkingConfig  `yaml:"networking,omitempty"`
-	Nodes         []NodeConfig      `yaml:"nodes,omitempty"`
+// ListClustersOptions filter and paginate the clusters listed by the ListClusters method of KindService
+// The selector matches the labels of the owners, clusters with an unknown creation time are never older than OlderThan
+type ListClustersOptions struct {
+	Selector labels.Selector
+	State KindClusterState
+	OlderThan time.Duration
+	Limit int
+	Continue string
 }
 
-// KindClusterStatus contains information about Kind cluster state and clontrol plane endpoint if available
-type KindClusterStatus struct {
-	State KindClusterState `json:"state"`
-	Host  string           `json:"host,omitempty"`
-	Port int               `json:"port,omitempty"`
+// KindInfo contains the versions of Kind and its container runtime and the numbers of existing clusters and nodes
+type KindInfo struct {
+	Provider string `json:"provider"`
+	RuntimeVersion string `json:"runtimeVersion"`
+	KindVersion string `json:"kindVersion"`
+	Clusters int `json:"clusters"`
+	Nodes int `json:"nodes"`
 }
 
 // NewKindClusterStatus creates a new instance of KindClusterStatus
@@ -83,4 +82,9 @@ func NewKindClusterStatus(state KindClusterState, rawServerUrl string) KindClust
 		port, _ = strconv.Atoi(portStr)
 	}
 	return KindClusterStatus{State: state, Host: host, Port: port}
-}
\ No newline at end of file
+}
+
+// NewFailedKindClusterStatus creates a new instance of KindClusterStatus in the failed state with the specified reason and message
+func NewFailedKindClusterStatus(reason, message string) KindClusterStatus {
+	return KindClusterStatus{State: KindClusterStateFailed, Reason: reason, Message: message}
+}
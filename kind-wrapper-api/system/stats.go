@@ -0,0 +1,72 @@
+package system
+
+import (
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"math"
+)
+
+// Resource contains the total and free amount of a host resource
+// CPU is measured in millicores, memory and disk in bytes
+type Resource struct {
+	Total int64 `json:"total"`
+	Free  int64 `json:"free"`
+}
+
+// Stats contains the resources of the host Kind clusters are created on
+type Stats struct {
+	CPUs   int      `json:"cpus"`
+	CPU    Resource `json:"cpu"`
+	Memory Resource `json:"memory"`
+	Disk   Resource `json:"disk"`
+}
+
+// Provider defines methods required to read the resources of the host
+type Provider interface {
+	Stats() (Stats, error)
+}
+
+// SystemProvider reads the resources of the host from the operating system
+type SystemProvider struct {
+	diskPath string
+}
+
+// NewSystemProvider creates a new instance of SystemProvider reporting the disk mounted at the specified path
+func NewSystemProvider(diskPath string) *SystemProvider {
+	return &SystemProvider{diskPath: diskPath}
+}
+
+// Stats reads the resources of the host
+// Free CPU is estimated as the number of logical CPUs reduced by the load average of the last minute
+func (p *SystemProvider) Stats() (Stats, error) {
+	cpus, err := cpu.Counts(true)
+	if err != nil {
+		return Stats{}, err
+	}
+	loadAvg, err := load.Avg()
+	if err != nil {
+		return Stats{}, err
+	}
+	memory, err := mem.VirtualMemory()
+	if err != nil {
+		return Stats{}, err
+	}
+	usage, err := disk.Usage(p.diskPath)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	totalCPU := int64(cpus) * 1000
+	freeCPU := totalCPU - int64(math.Round(loadAvg.Load1*1000))
+	if freeCPU < 0 {
+		freeCPU = 0
+	}
+	return Stats{
+		CPUs:   cpus,
+		CPU:    Resource{Total: totalCPU, Free: freeCPU},
+		Memory: Resource{Total: int64(memory.Total), Free: int64(memory.Available)},
+		Disk:   Resource{Total: int64(usage.Total), Free: int64(usage.Free)},
+	}, nil
+}
@@ -0,0 +1,26 @@
+package system
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestSystemProvider(t *testing.T) {
+	t.Run("test read host stats", func(t *testing.T) {
+		stats, err := NewSystemProvider(os.TempDir()).Stats()
+		require.NoError(t, err)
+		require.Greater(t, stats.CPUs, 0)
+		require.Equal(t, int64(stats.CPUs)*1000, stats.CPU.Total)
+		require.LessOrEqual(t, stats.CPU.Free, stats.CPU.Total)
+		require.Greater(t, stats.Memory.Total, int64(0))
+		require.LessOrEqual(t, stats.Memory.Free, stats.Memory.Total)
+		require.Greater(t, stats.Disk.Total, int64(0))
+		require.LessOrEqual(t, stats.Disk.Free, stats.Disk.Total)
+	})
+
+	t.Run("test read stats of a missing disk", func(t *testing.T) {
+		_, err := NewSystemProvider("/path/does/not/exist").Stats()
+		require.Error(t, err)
+	})
+}
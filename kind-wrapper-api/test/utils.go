@@ -1,7 +1,10 @@
 package test
 
 import (
+	"kind-wrapper-api/kind"
+	"kind-wrapper-api/system"
 	"os"
+	"time"
 )
 
 const EmptyKubeConfig = `---
@@ -33,11 +36,18 @@ users:
     client-certificate-data: ""
     client-key-data: ""`
 
+const MockKindVersion = "0.18.0"
+
 type MockKindClient struct {
 	hasNodesQueue []func() (bool, error)
 	defaultHasNodes func() (bool, error)
 	create func() error
 	delete func() error
+	clusters map[string]int
+	nodes map[string][]kind.Node
+	creationTimes map[string]time.Time
+	runtimeVersion string
+	runtimeErr error
 }
 
 func NewMockKindClient() *MockKindClient {
@@ -52,6 +62,10 @@ func NewMockKindClient() *MockKindClient {
 		delete: func() error {
 			return nil
 		},
+		clusters: map[string]int{},
+		nodes: map[string][]kind.Node{},
+		creationTimes: map[string]time.Time{},
+		runtimeVersion: "20.10.17",
 	}
 }
 
@@ -75,6 +89,23 @@ func (m *MockKindClient) SetDelete(delete func() error) {
 	m.delete = delete
 }
 
+func (m *MockKindClient) SetClusters(clusters map[string]int) {
+	m.clusters = clusters
+}
+
+func (m *MockKindClient) SetNodes(name string, nodes []kind.Node) {
+	m.nodes[name] = nodes
+}
+
+func (m *MockKindClient) SetCreationTime(name string, created time.Time) {
+	m.creationTimes[name] = created
+}
+
+func (m *MockKindClient) SetRuntimeVersion(runtimeVersion string, err error) {
+	m.runtimeVersion = runtimeVersion
+	m.runtimeErr = err
+}
+
 func (m *MockKindClient) CreateCluster(_ string, _ []byte) error {
 	return m.create()
 }
@@ -92,6 +123,51 @@ func (m *MockKindClient) ClusterHasNodes(_ string) (bool, error) {
 	return m.defaultHasNodes()
 }
 
+func (m *MockKindClient) ListClusters() ([]string, error) {
+	var names []string
+	for name := range m.clusters {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (m *MockKindClient) CountNodes(name string) (int, error) {
+	return m.clusters[name], nil
+}
+
+func (m *MockKindClient) ListNodes(name string) ([]kind.Node, error) {
+	return m.nodes[name], nil
+}
+
+func (m *MockKindClient) CreationTime(name string) (time.Time, error) {
+	return m.creationTimes[name], nil
+}
+
+func (m *MockKindClient) KubeConfig(_ string) (string, error) {
+	return KubeConfig, nil
+}
+
+func (m *MockKindClient) RuntimeVersion() (string, error) {
+	return m.runtimeVersion, m.runtimeErr
+}
+
+func (m *MockKindClient) Version() string {
+	return MockKindVersion
+}
+
+type MockStatsProvider struct {
+	stats system.Stats
+	err   error
+}
+
+func NewMockStatsProvider(stats system.Stats, err error) *MockStatsProvider {
+	return &MockStatsProvider{stats: stats, err: err}
+}
+
+func (m *MockStatsProvider) Stats() (system.Stats, error) {
+	return m.stats, m.err
+}
+
 func SetupKubeConfig(dir string, content string) (string, error) {
 	kubeConfigFile, err := os.CreateTemp(dir, "config")
 	if err != nil {